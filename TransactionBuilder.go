@@ -15,10 +15,11 @@ import (
 )
 
 type TransactionBuilder struct {
-	inputs    []Input
-	outputs   []Output
-	netParams *chaincfg.Params
-	tx        *wire.MsgTx
+	inputs           []Input
+	outputs          []Output
+	netParams        *chaincfg.Params
+	tx               *wire.MsgTx
+	allowAssetInputs bool
 }
 
 func (t *TransactionBuilder) TotalInputAmount() int64 {
@@ -44,6 +45,7 @@ type Input struct {
 	redeemScript  string
 	address       string
 	amount        int64
+	assets        *UtxoAssets
 }
 
 type Output struct {
@@ -112,6 +114,105 @@ func (build *TransactionBuilder) AddInput2(txId string, vOut uint32, privateKey
 	build.inputs = append(build.inputs, input)
 }
 
+// AddInputWithAssets 添加携带铭文/符文的输入，需调用 AllowAssetInputs 后才允许构建
+func (build *TransactionBuilder) AddInputWithAssets(txId string, vOut uint32, privateKey string, address string, amount int64, assets *UtxoAssets) {
+	input := Input{txId: txId, vOut: vOut, privateKeyHex: privateKey, address: address, amount: amount, assets: assets}
+	build.inputs = append(build.inputs, input)
+}
+
+// AllowAssetInputs 明确转移资产 UTXO，构建时只校验铭文 sat 不会落入手续费或 OP_RETURN 输出，不会调整输出顺序；
+// 需要把铭文放到指定输出时使用 AddInscriptionOutput
+func (build *TransactionBuilder) AllowAssetInputs() {
+	build.allowAssetInputs = true
+}
+
+// AddInscriptionOutput 追加 address 输出，使铭文 sat 位于该输出的开头（偏移 0）。
+// 已有输出的总额超过铭文 sat 位置时返回错误；不足时先追加一个给 paddingAddress 的填充输出，填充金额不能低于 546
+func (build *TransactionBuilder) AddInscriptionOutput(inscriptionId string, address string, amount int64, paddingAddress string) error {
+	if amount <= 0 {
+		return errors.New("inscription output amount must be positive")
+	}
+	position := int64(-1)
+	inputPosition := int64(0)
+	for _, v := range build.inputs {
+		if v.assets != nil {
+			for _, inscription := range v.assets.Inscriptions {
+				if inscription.InscriptionId == inscriptionId {
+					position = inputPosition + inscription.Offset
+				}
+			}
+		}
+		inputPosition += v.amount
+	}
+	if position < 0 {
+		return fmt.Errorf("inscription %s not found in inputs", inscriptionId)
+	}
+	padding := position - build.TotalOutputAmount()
+	if padding < 0 {
+		return fmt.Errorf("inscription %s at sat %d already covered by existing outputs", inscriptionId, position)
+	}
+	if padding > 0 {
+		if padding < DefaultMinChangeValue {
+			return fmt.Errorf("padding %d less than dust %d, reorder inputs or outputs", padding, DefaultMinChangeValue)
+		}
+		build.AddOutput(paddingAddress, padding)
+	}
+	build.AddOutput(address, amount)
+	return nil
+}
+
+// LocateInscriptions 按当前输入输出顺序计算每个铭文落在哪个输出
+func (build *TransactionBuilder) LocateInscriptions() []*SatLocation {
+	inputAmounts, inputAssets, outputAmounts := build.assetFlow()
+	return LocateInscriptions(inputAmounts, inputAssets, outputAmounts)
+}
+
+func (build *TransactionBuilder) assetFlow() ([]int64, []*UtxoAssets, []int64) {
+	inputAmounts := make([]int64, len(build.inputs))
+	inputAssets := make([]*UtxoAssets, len(build.inputs))
+	for i, v := range build.inputs {
+		inputAmounts[i] = v.amount
+		inputAssets[i] = v.assets
+	}
+	outputAmounts := make([]int64, len(build.outputs))
+	for i, v := range build.outputs {
+		outputAmounts[i] = v.amount
+	}
+	return inputAmounts, inputAssets, outputAmounts
+}
+
+func (build *TransactionBuilder) checkAssetInputs() error {
+	hasAsset := false
+	for i, v := range build.inputs {
+		if !v.assets.HasAsset() {
+			continue
+		}
+		if !build.allowAssetInputs {
+			return fmt.Errorf("input %d (%s:%d): %w", i, v.txId, v.vOut, ErrAssetUtxo)
+		}
+		hasAsset = true
+	}
+	if !hasAsset {
+		return nil
+	}
+	outputPkScripts := make([][]byte, len(build.outputs))
+	for i, output := range build.outputs {
+		var pkScript []byte
+		var err error
+		if len(output.script) != 0 && len(output.address) == 0 {
+			pkScript, err = hex.DecodeString(output.script)
+		} else {
+			pkScript, err = AddrToPkScript(output.address, build.netParams)
+		}
+		if err != nil {
+			return err
+		}
+		outputPkScripts[i] = pkScript
+	}
+	inputAmounts, inputAssets, outputAmounts := build.assetFlow()
+	return checkAssetRouting(inputAmounts, inputAssets, outputAmounts, outputPkScripts)
+}
+
 func (build *TransactionBuilder) AddOutput(address string, amount int64) {
 	output := Output{address: address, amount: amount}
 	build.outputs = append(build.outputs, output)
@@ -126,6 +227,9 @@ func (build *TransactionBuilder) Build() (*wire.MsgTx, error) {
 	if len(build.inputs) == 0 || len(build.outputs) == 0 {
 		return nil, errors.New("invalid inputs or outputs")
 	}
+	if err := build.checkAssetInputs(); err != nil {
+		return nil, err
+	}
 
 	tx := build.tx
 	prevOutFetcher := txscript.NewMultiPrevOutFetcher(nil)
@@ -182,6 +286,9 @@ func (build *TransactionBuilder) SingleBuild() (string, error) {
 	if len(build.inputs) == 0 || len(build.outputs) == 0 {
 		return "", errors.New("invalid inputs or outputs")
 	}
+	if err := build.checkAssetInputs(); err != nil {
+		return "", err
+	}
 
 	tx := build.tx
 	var scriptArray [][]byte
@@ -271,6 +378,9 @@ func (build *TransactionBuilder) UnSignedTx(pubKeyMap map[int]string) (string, m
 	if len(build.inputs) == 0 || len(build.outputs) == 0 {
 		return "", nil, fmt.Errorf("input or output miss")
 	}
+	if err := build.checkAssetInputs(); err != nil {
+		return "", nil, err
+	}
 	tx := build.tx
 	var scriptArray [][]byte
 	for i := 0; i < len(build.inputs); i++ {
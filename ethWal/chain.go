@@ -0,0 +1,169 @@
+package ethWal
+
+import (
+	"errors"
+	"fmt"
+	"github.com/PandaManPMC/txBuilder/evmAbi"
+	"github.com/PandaManPMC/txBuilder/hdWallet"
+	"math/big"
+	"sort"
+	"sync"
+)
+
+// Chain EVM 网络信息。gas limit 默认值仅用于离线构建时未指定 Gas 的情况，能联网时建议 eth_estimateGas
+type Chain struct {
+	Name string `json:"name"`
+	// NetWorks 网络名称及别名，同一名称只属于一个网络，重新注册时从原网络移除
+	NetWorks       []string `json:"netWorks"`
+	ChainId        *big.Int `json:"chainId"`
+	NativeSymbol   string   `json:"nativeSymbol"`
+	NativeDecimals uint8    `json:"nativeDecimals"`
+	// EIP1559 是否支持 type 2 交易
+	EIP1559 bool `json:"eip1559"`
+	// TransferGasLimit 原生币转账
+	TransferGasLimit uint64 `json:"transferGasLimit"`
+	// TokenTransferGasLimit ERC-20 transfer
+	TokenTransferGasLimit uint64 `json:"tokenTransferGasLimit"`
+	Explorer              string `json:"explorer"`
+}
+
+var (
+	chainLock       sync.RWMutex
+	chainsById      = make(map[string]*Chain)
+	chainsByNetWork = make(map[string]*Chain)
+)
+
+func init() {
+	for _, chain := range []*Chain{
+		{Name: "Ethereum", NetWorks: []string{"Ethereum", "ETH"}, ChainId: big.NewInt(1), NativeSymbol: "ETH", EIP1559: true, Explorer: "https://etherscan.io"},
+		{Name: "BNB Smart Chain", NetWorks: []string{"BNB Smart Chain", "BSC", "BNB"}, ChainId: big.NewInt(56), NativeSymbol: "BNB", EIP1559: true, Explorer: "https://bscscan.com"},
+		{Name: "Polygon", NetWorks: []string{"Polygon", "POL", "Matic"}, ChainId: big.NewInt(137), NativeSymbol: "POL", EIP1559: true, Explorer: "https://polygonscan.com"},
+		{Name: "Arbitrum One", NetWorks: []string{"Arbitrum One", "Arbitrum", "ARB"}, ChainId: big.NewInt(42161), NativeSymbol: "ETH", EIP1559: true,
+			// Arbitrum 的 gasUsed 包含 L1 数据费用，明显高于 L1
+			TransferGasLimit: 100000, TokenTransferGasLimit: 300000, Explorer: "https://arbiscan.io"},
+		{Name: "Optimism", NetWorks: []string{"Optimism", "OP"}, ChainId: big.NewInt(10), NativeSymbol: "ETH", EIP1559: true, Explorer: "https://optimistic.etherscan.io"},
+		{Name: "Base", NetWorks: []string{"Base"}, ChainId: big.NewInt(8453), NativeSymbol: "ETH", EIP1559: true, Explorer: "https://basescan.org"},
+		{Name: "Avalanche C-Chain", NetWorks: []string{"Avalanche C-Chain", "Avalanche", "AVAX"}, ChainId: big.NewInt(43114), NativeSymbol: "AVAX", EIP1559: true, Explorer: "https://snowtrace.io"},
+	} {
+		if err := RegisterChain(chain); nil != err {
+			panic(err)
+		}
+	}
+}
+
+// RegisterChain 注册或覆盖 EVM 网络，自定义 L2 在运行时注册后即可用于 ChainIdByNetWork、BuildTx 与 CoinTypeByNetWork。
+// NativeDecimals 为 0 时取 18，gas limit 为 0 时取 21000/65000
+func RegisterChain(chain *Chain) error {
+	if chain == nil || chain.ChainId == nil || chain.ChainId.Sign() <= 0 {
+		return errors.New("chain miss chainId")
+	}
+	if len(chain.NetWorks) == 0 {
+		return errors.New("chain miss netWorks")
+	}
+	c := *chain
+	c.ChainId = new(big.Int).Set(chain.ChainId)
+	c.NetWorks = append([]string{}, chain.NetWorks...)
+	if c.Name == "" {
+		c.Name = c.NetWorks[0]
+	}
+	if c.NativeDecimals == 0 {
+		c.NativeDecimals = 18
+	}
+	if c.TransferGasLimit == 0 {
+		c.TransferGasLimit = 21000
+	}
+	if c.TokenTransferGasLimit == 0 {
+		c.TokenTransferGasLimit = 65000
+	}
+
+	chainLock.Lock()
+	defer chainLock.Unlock()
+	if old, ok := chainsById[c.ChainId.String()]; ok {
+		for _, netWork := range old.NetWorks {
+			delete(chainsByNetWork, netWork)
+		}
+	}
+	for _, netWork := range c.NetWorks {
+		// 名称已属于其他网络时从其 NetWorks 中移除，避免该网络重新注册时删掉新的映射
+		if holder, ok := chainsByNetWork[netWork]; ok && holder.ChainId.Cmp(c.ChainId) != 0 {
+			netWorks := make([]string, 0, len(holder.NetWorks))
+			for _, name := range holder.NetWorks {
+				if name != netWork {
+					netWorks = append(netWorks, name)
+				}
+			}
+			holder.NetWorks = netWorks
+		}
+		chainsByNetWork[netWork] = &c
+	}
+	chainsById[c.ChainId.String()] = &c
+	return nil
+}
+
+// defaultGasLimit 只对原生币转账与 ERC-20 transfer 给出默认值，其他合约调用返回 0
+func (c *Chain) defaultGasLimit(data []byte) uint64 {
+	if len(data) == 0 {
+		return c.TransferGasLimit
+	}
+	if call, err := evmAbi.DecodeTokenCall(data); nil == err && call.Method == evmAbi.SigTransfer {
+		return c.TokenTransferGasLimit
+	}
+	return 0
+}
+
+// ChainByNetWork 按网络名称查找
+func ChainByNetWork(netWork string) (*Chain, error) {
+	chainLock.RLock()
+	defer chainLock.RUnlock()
+	chain, ok := chainsByNetWork[netWork]
+	if !ok {
+		return nil, fmt.Errorf("%s not found", netWork)
+	}
+	c := *chain
+	return &c, nil
+}
+
+// ChainById 按 chainId 查找
+func ChainById(chainId *big.Int) (*Chain, error) {
+	if chainId == nil {
+		return nil, errors.New("chainId miss")
+	}
+	chainLock.RLock()
+	defer chainLock.RUnlock()
+	chain, ok := chainsById[chainId.String()]
+	if !ok {
+		return nil, fmt.Errorf("chain %s not found", chainId)
+	}
+	c := *chain
+	return &c, nil
+}
+
+// Chains 已注册的全部网络，按 chainId 升序
+func Chains() []*Chain {
+	chainLock.RLock()
+	defer chainLock.RUnlock()
+	list := make([]*Chain, 0, len(chainsById))
+	for _, chain := range chainsById {
+		c := *chain
+		list = append(list, &c)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ChainId.Cmp(list[j].ChainId) < 0 })
+	return list
+}
+
+// ChainIdByNetWork 按网络名称取 chainId
+func ChainIdByNetWork(netWork string) (*big.Int, error) {
+	chain, err := ChainByNetWork(netWork)
+	if nil != err {
+		return nil, err
+	}
+	return chain.ChainId, nil
+}
+
+// CoinTypeByNetWork 已注册的 EVM 网络返回 ETHHDCoinType，其他名称交给 hdWallet.GetCoinTypeByNetWork
+func CoinTypeByNetWork(netWork string) (hdWallet.HDCoinType, error) {
+	if _, err := ChainByNetWork(netWork); nil == err {
+		return hdWallet.ETHHDCoinType, nil
+	}
+	return hdWallet.GetCoinTypeByNetWork(netWork)
+}
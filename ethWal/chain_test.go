@@ -0,0 +1,99 @@
+package ethWal
+
+import (
+	"github.com/PandaManPMC/txBuilder/evmAbi"
+	"github.com/PandaManPMC/txBuilder/hdWallet"
+	"math/big"
+	"testing"
+)
+
+func TestChainRegistry(t *testing.T) {
+	for netWork, chainId := range map[string]int64{"ETH": 1, "BSC": 56, "BNB": 56, "POL": 137, "Matic": 137, "Base": 8453} {
+		id, err := ChainIdByNetWork(netWork)
+		if nil != err {
+			t.Fatal(err)
+		}
+		if id.Int64() != chainId {
+			t.Fatalf("%s chainId %s", netWork, id)
+		}
+	}
+	if _, err := ChainIdByNetWork("Unknown"); nil == err {
+		t.Fatal("expected unknown network error")
+	}
+	if _, err := CoinTypeByNetWork("Unknown"); nil == err {
+		t.Fatal("expected unknown coin type error")
+	}
+	if coinType, err := CoinTypeByNetWork("Arbitrum"); nil != err || coinType != hdWallet.ETHHDCoinType {
+		t.Fatalf("arbitrum coin type %d %v", coinType, err)
+	}
+	if coinType, err := CoinTypeByNetWork("BTC"); nil != err || coinType != hdWallet.BTCHDCoinType {
+		t.Fatalf("btc coin type %d %v", coinType, err)
+	}
+	// 注册表不会写入 hdWallet，hdWallet 的结果与是否导入 ethWal 无关
+	if _, err := hdWallet.GetCoinTypeByNetWork("Arbitrum"); nil == err {
+		t.Fatal("hdWallet should not know registry networks")
+	}
+
+	// 运行时注册自定义 L2，不支持 EIP-1559
+	err := RegisterChain(&Chain{Name: "Custom L2", NetWorks: []string{"CustomL2"}, ChainId: big.NewInt(990001), NativeSymbol: "CETH", TransferGasLimit: 30000})
+	if nil != err {
+		t.Fatal(err)
+	}
+	if coinType, _ := CoinTypeByNetWork("CustomL2"); coinType != hdWallet.ETHHDCoinType {
+		t.Fatal("custom network coin type mismatch")
+	}
+	chain, err := ChainById(big.NewInt(990001))
+	if nil != err {
+		t.Fatal(err)
+	}
+	if chain.NativeDecimals != 18 || chain.TokenTransferGasLimit != 65000 {
+		t.Fatalf("chain defaults %+v", chain)
+	}
+
+	tx, err := BuildTx(&TxRequest{ChainId: chain.ChainId, Type: LegacyTxType, To: nonceTestAddress, GasPrice: big.NewInt(1)})
+	if nil != err {
+		t.Fatal(err)
+	}
+	if tx.Gas() != 30000 {
+		t.Fatalf("default gas %d", tx.Gas())
+	}
+	data, _ := evmAbi.ERC20Transfer(nonceTestAddress, big.NewInt(1))
+	tx, _ = BuildTx(&TxRequest{ChainId: chain.ChainId, Type: LegacyTxType, To: nonceTestAddress, GasPrice: big.NewInt(1), Data: data})
+	if tx.Gas() != 65000 {
+		t.Fatalf("default token gas %d", tx.Gas())
+	}
+	if _, err := BuildTx(&TxRequest{ChainId: chain.ChainId, Type: DynamicFeeTxType, To: nonceTestAddress, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(1)}); nil == err {
+		t.Fatal("expected EIP-1559 not supported")
+	}
+	if _, err := BuildTx(&TxRequest{ChainId: chain.ChainId, Type: LegacyTxType, To: nonceTestAddress, GasPrice: big.NewInt(1), Data: []byte{1, 2, 3, 4}}); nil == err {
+		t.Fatal("expected gas miss for unknown contract call")
+	}
+}
+
+func TestRegisterChainMoveNetWork(t *testing.T) {
+	if err := RegisterChain(&Chain{NetWorks: []string{"MoveA", "MoveShared"}, ChainId: big.NewInt(990002)}); nil != err {
+		t.Fatal(err)
+	}
+	// MoveShared 转给新网络后，原网络的 NetWorks 不再包含它
+	if err := RegisterChain(&Chain{NetWorks: []string{"MoveShared"}, ChainId: big.NewInt(990003)}); nil != err {
+		t.Fatal(err)
+	}
+	old, _ := ChainById(big.NewInt(990002))
+	if len(old.NetWorks) != 1 || old.NetWorks[0] != "MoveA" {
+		t.Fatalf("old netWorks %v", old.NetWorks)
+	}
+	// 重新注册原网络不能删掉新网络的名称
+	if err := RegisterChain(old); nil != err {
+		t.Fatal(err)
+	}
+	if chainId, err := ChainIdByNetWork("MoveShared"); nil != err || chainId.Int64() != 990003 {
+		t.Fatalf("MoveShared chainId %v %v", chainId, err)
+	}
+	// 从 NetWorks 中去掉的名称不再可查
+	if err := RegisterChain(&Chain{NetWorks: []string{"MoveB"}, ChainId: big.NewInt(990002)}); nil != err {
+		t.Fatal(err)
+	}
+	if _, err := CoinTypeByNetWork("MoveA"); nil == err {
+		t.Fatal("expected MoveA removed")
+	}
+}
@@ -0,0 +1,326 @@
+package ethWal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"io"
+	"math/big"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Rpc EVM 节点常用接口，Client 为 JSON-RPC 实现，测试或其他数据源可自行实现
+type Rpc interface {
+	ChainId() (*big.Int, error)
+	PendingNonce(chainId *big.Int, address string) (uint64, error)
+	GasPrice() (*big.Int, error)
+	MaxPriorityFeePerGas() (*big.Int, error)
+	FeeHistory(blockCount uint64, rewardPercentiles []float64) (*FeeHistory, error)
+	Balance(address string) (*big.Int, error)
+	SendRawTransaction(rawTx string) (string, error)
+	TransactionReceipt(txHash string) (*Receipt, error)
+}
+
+var _ Rpc = (*Client)(nil)
+
+// Client EVM JSON-RPC 客户端，HTTPClient 为空时使用 http.DefaultClient
+type Client struct {
+	URL        string
+	AuthHeader string
+	Ctx        context.Context
+	HTTPClient *http.Client
+
+	id          uint64
+	chainIdLock sync.Mutex
+	chainId     *big.Int
+}
+
+// RpcError 节点返回的 JSON-RPC 错误
+type RpcError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *RpcError) Error() string {
+	if len(e.Data) > 0 {
+		return fmt.Sprintf("rpc error %d: %s %s", e.Code, e.Message, string(e.Data))
+	}
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
+// Call 调用任意 JSON-RPC 方法，result 为 nil 时丢弃结果
+func (c *Client) Call(method string, result interface{}, params ...interface{}) error {
+	if params == nil {
+		params = []interface{}{}
+	}
+	body := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      atomic.AddUint64(&c.id, 1),
+		"method":  method,
+		"params":  params,
+	}
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(body); err != nil {
+		return err
+	}
+	ctx := c.Ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.AuthHeader != "" {
+		req.Header.Set("Authorization", c.AuthHeader)
+	}
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var v struct {
+		Result json.RawMessage `json:"result"`
+		Error  *RpcError       `json:"error"`
+	}
+	if err = json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("http status %d, unmarshal rpc response error: %v", resp.StatusCode, err)
+	}
+	if v.Error != nil {
+		return v.Error
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(v.Result, result)
+}
+
+func (c *Client) callBig(method string, params ...interface{}) (*big.Int, error) {
+	var result hexutil.Big
+	if err := c.Call(method, &result, params...); nil != err {
+		return nil, err
+	}
+	return result.ToInt(), nil
+}
+
+// ChainId eth_chainId，结果会缓存
+func (c *Client) ChainId() (*big.Int, error) {
+	c.chainIdLock.Lock()
+	defer c.chainIdLock.Unlock()
+	if c.chainId == nil {
+		chainId, err := c.callBig("eth_chainId")
+		if nil != err {
+			return nil, err
+		}
+		c.chainId = chainId
+	}
+	return new(big.Int).Set(c.chainId), nil
+}
+
+// PendingNonce eth_getTransactionCount(address, "pending")，chainId 不为空时校验节点所在链，可作为 NonceManager 的 NonceSource
+func (c *Client) PendingNonce(chainId *big.Int, address string) (uint64, error) {
+	if chainId != nil {
+		nodeChainId, err := c.ChainId()
+		if nil != err {
+			return 0, err
+		}
+		if nodeChainId.Cmp(chainId) != 0 {
+			return 0, fmt.Errorf("chain id mismatch: node %s, expect %s", nodeChainId, chainId)
+		}
+	}
+	var result hexutil.Uint64
+	if err := c.Call("eth_getTransactionCount", &result, address, "pending"); nil != err {
+		return 0, err
+	}
+	return uint64(result), nil
+}
+
+// GasPrice eth_gasPrice，legacy 交易使用
+func (c *Client) GasPrice() (*big.Int, error) {
+	return c.callBig("eth_gasPrice")
+}
+
+// MaxPriorityFeePerGas eth_maxPriorityFeePerGas
+func (c *Client) MaxPriorityFeePerGas() (*big.Int, error) {
+	return c.callBig("eth_maxPriorityFeePerGas")
+}
+
+// Balance eth_getBalance(address, "latest")，单位 wei
+func (c *Client) Balance(address string) (*big.Int, error) {
+	return c.callBig("eth_getBalance", address, "latest")
+}
+
+// SendRawTransaction eth_sendRawTransaction，返回交易 hash
+func (c *Client) SendRawTransaction(rawTx string) (string, error) {
+	var txHash common.Hash
+	if err := c.Call("eth_sendRawTransaction", &txHash, rawTx); nil != err {
+		return "", err
+	}
+	return txHash.Hex(), nil
+}
+
+// FeeHistory eth_feeHistory 结果，BaseFeePerGas 比区块数多一个，最后一个为下一个区块的 baseFee
+type FeeHistory struct {
+	OldestBlock   *big.Int     `json:"oldestBlock"`
+	BaseFeePerGas []*big.Int   `json:"baseFeePerGas"`
+	GasUsedRatio  []float64    `json:"gasUsedRatio"`
+	Reward        [][]*big.Int `json:"reward"`
+}
+
+// FeeHistory eth_feeHistory(blockCount, "latest", rewardPercentiles)
+func (c *Client) FeeHistory(blockCount uint64, rewardPercentiles []float64) (*FeeHistory, error) {
+	var result struct {
+		OldestBlock   *hexutil.Big     `json:"oldestBlock"`
+		BaseFeePerGas []*hexutil.Big   `json:"baseFeePerGas"`
+		GasUsedRatio  []float64        `json:"gasUsedRatio"`
+		Reward        [][]*hexutil.Big `json:"reward"`
+	}
+	if err := c.Call("eth_feeHistory", &result, hexutil.Uint64(blockCount), "latest", rewardPercentiles); nil != err {
+		return nil, err
+	}
+	history := &FeeHistory{
+		OldestBlock:  (*big.Int)(result.OldestBlock),
+		GasUsedRatio: result.GasUsedRatio,
+	}
+	for _, v := range result.BaseFeePerGas {
+		history.BaseFeePerGas = append(history.BaseFeePerGas, (*big.Int)(v))
+	}
+	for _, block := range result.Reward {
+		rewards := make([]*big.Int, len(block))
+		for i, v := range block {
+			rewards[i] = (*big.Int)(v)
+		}
+		history.Reward = append(history.Reward, rewards)
+	}
+	return history, nil
+}
+
+// ReceiptLog 交易回执中的事件，Topics/Data 可交给 evmAbi.DecodeTransferLog 解析
+type ReceiptLog struct {
+	Address common.Address `json:"address"`
+	Topics  []common.Hash  `json:"topics"`
+	Data    hexutil.Bytes  `json:"data"`
+}
+
+// Receipt 交易回执，Status 1 成功 0 失败
+type Receipt struct {
+	TxHash            common.Hash     `json:"transactionHash"`
+	BlockHash         common.Hash     `json:"blockHash"`
+	BlockNumber       *hexutil.Big    `json:"blockNumber"`
+	Status            hexutil.Uint64  `json:"status"`
+	GasUsed           hexutil.Uint64  `json:"gasUsed"`
+	EffectiveGasPrice *hexutil.Big    `json:"effectiveGasPrice"`
+	ContractAddress   *common.Address `json:"contractAddress"`
+	Logs              []*ReceiptLog   `json:"logs"`
+}
+
+// TransactionReceipt eth_getTransactionReceipt，交易未上链时返回 nil, nil
+func (c *Client) TransactionReceipt(txHash string) (*Receipt, error) {
+	var receipt *Receipt
+	if err := c.Call("eth_getTransactionReceipt", &receipt, txHash); nil != err {
+		return nil, err
+	}
+	return receipt, nil
+}
+
+// WaitReceipt 每隔 interval 轮询回执，直到上链或 timeout
+func WaitReceipt(rpc Rpc, txHash string, interval, timeout time.Duration) (*Receipt, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		receipt, err := rpc.TransactionReceipt(txHash)
+		if nil != err {
+			return nil, err
+		}
+		if receipt != nil {
+			return receipt, nil
+		}
+		if time.Now().Add(interval).After(deadline) {
+			return nil, fmt.Errorf("wait receipt %s timeout", txHash)
+		}
+		time.Sleep(interval)
+	}
+}
+
+// 费用建议使用的区块数与奖励百分位（慢、标准、快）
+const feeHistoryBlocks = 20
+
+var feeHistoryPercentiles = []float64{10, 50, 90}
+
+// Fee EIP-1559 费用，可直接填入 TxRequest 的 GasTipCap/GasFeeCap
+type Fee struct {
+	GasTipCap *big.Int `json:"maxPriorityFeePerGas"`
+	GasFeeCap *big.Int `json:"maxFeePerGas"`
+}
+
+// FeeSuggestion 按近期区块小费百分位给出的费用建议，GasFeeCap = 2 * 下一区块 baseFee + 小费，可承受 baseFee 连续上涨
+type FeeSuggestion struct {
+	BaseFee  *big.Int `json:"baseFee"`
+	Slow     *Fee     `json:"slow"`
+	Standard *Fee     `json:"standard"`
+	Fast     *Fee     `json:"fast"`
+}
+
+// SuggestFee 根据 eth_feeHistory 最近 20 个区块第 10/50/90 百分位小费的中位数计算费用
+func SuggestFee(rpc Rpc) (*FeeSuggestion, error) {
+	history, err := rpc.FeeHistory(feeHistoryBlocks, feeHistoryPercentiles)
+	if nil != err {
+		return nil, err
+	}
+	return SuggestFeeFromHistory(history)
+}
+
+// SuggestFeeFromHistory 由已获取的 FeeHistory 计算费用建议，history 须按 10/50/90 百分位获取
+func SuggestFeeFromHistory(history *FeeHistory) (*FeeSuggestion, error) {
+	if len(history.BaseFeePerGas) == 0 {
+		return nil, errors.New("fee history miss baseFeePerGas, chain may not support EIP-1559")
+	}
+	baseFee := history.BaseFeePerGas[len(history.BaseFeePerGas)-1]
+	tips := make([]*big.Int, len(feeHistoryPercentiles))
+	for i := range feeHistoryPercentiles {
+		rewards := make([]*big.Int, 0, len(history.Reward))
+		for _, block := range history.Reward {
+			if i < len(block) && block[i] != nil {
+				rewards = append(rewards, block[i])
+			}
+		}
+		tips[i] = medianBig(rewards)
+	}
+	fee := func(tip *big.Int) *Fee {
+		feeCap := new(big.Int).Mul(baseFee, big.NewInt(2))
+		return &Fee{GasTipCap: tip, GasFeeCap: feeCap.Add(feeCap, tip)}
+	}
+	return &FeeSuggestion{
+		BaseFee:  new(big.Int).Set(baseFee),
+		Slow:     fee(tips[0]),
+		Standard: fee(tips[1]),
+		Fast:     fee(tips[2]),
+	}, nil
+}
+
+func medianBig(list []*big.Int) *big.Int {
+	if len(list) == 0 {
+		return new(big.Int)
+	}
+	sorted := make([]*big.Int, len(list))
+	copy(sorted, list)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Cmp(sorted[j]) < 0 })
+	return new(big.Int).Set(sorted[len(sorted)/2])
+}
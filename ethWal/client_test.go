@@ -0,0 +1,203 @@
+package ethWal
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/PandaManPMC/txBuilder/hdWallet"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeNode 进程内的假节点，按 method 返回固定结果，记录广播的交易
+type fakeNode struct {
+	lock     sync.Mutex
+	nonce    uint64
+	sent     []string
+	receipts map[string]int
+}
+
+func (n *fakeNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Id     uint64            `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	json.NewDecoder(r.Body).Decode(&req)
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	var result interface{}
+	var rpcErr *RpcError
+	switch req.Method {
+	case "eth_chainId":
+		result = "0x1"
+	case "eth_getTransactionCount":
+		result = hexutil.Uint64(n.nonce)
+	case "eth_gasPrice":
+		result = "0x4a817c800"
+	case "eth_maxPriorityFeePerGas":
+		result = "0x3b9aca00"
+	case "eth_getBalance":
+		result = "0xde0b6b3a7640000"
+	case "eth_feeHistory":
+		result = map[string]interface{}{
+			"oldestBlock":   "0x10",
+			"baseFeePerGas": []string{"0x64", "0x6e", "0x78", "0x82"},
+			"gasUsedRatio":  []float64{0.5, 0.9, 0.7},
+			"reward":        [][]string{{"0x1", "0x5", "0xa"}, {"0x2", "0x6", "0x14"}, {"0x3", "0x7", "0x1e"}},
+		}
+	case "eth_sendRawTransaction":
+		var raw string
+		json.Unmarshal(req.Params[0], &raw)
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(hexutil.MustDecode(raw)); nil != err {
+			rpcErr = &RpcError{Code: -32000, Message: err.Error()}
+			break
+		}
+		if tx.Nonce() != n.nonce {
+			rpcErr = &RpcError{Code: -32000, Message: "nonce too low"}
+			break
+		}
+		n.nonce++
+		n.sent = append(n.sent, raw)
+		n.receipts[tx.Hash().Hex()] = 0
+		result = tx.Hash().Hex()
+	case "eth_getTransactionReceipt":
+		var txHash string
+		json.Unmarshal(req.Params[0], &txHash)
+		polls, ok := n.receipts[txHash]
+		if !ok || polls < 2 {
+			// 前两次轮询返回 null 模拟未上链
+			n.receipts[txHash] = polls + 1
+			result = nil
+			break
+		}
+		result = map[string]interface{}{
+			"transactionHash":   txHash,
+			"blockHash":         "0x0000000000000000000000000000000000000000000000000000000000000001",
+			"blockNumber":       "0x13",
+			"status":            "0x1",
+			"gasUsed":           "0x5208",
+			"effectiveGasPrice": "0x3b9aca00",
+			"contractAddress":   nil,
+			"logs":              []interface{}{},
+		}
+	default:
+		rpcErr = &RpcError{Code: -32601, Message: "method not found"}
+	}
+	resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.Id}
+	if rpcErr != nil {
+		resp["error"] = rpcErr
+	} else {
+		resp["result"] = result
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+func newFakeNode(t *testing.T) (*fakeNode, *Client) {
+	node := &fakeNode{nonce: 3, receipts: make(map[string]int)}
+	server := httptest.NewServer(node)
+	t.Cleanup(server.Close)
+	return node, &Client{URL: server.URL}
+}
+
+func TestClient(t *testing.T) {
+	_, client := newFakeNode(t)
+	chainId, err := client.ChainId()
+	if nil != err || chainId.Int64() != 1 {
+		t.Fatalf("chainId %v %v", chainId, err)
+	}
+	gasPrice, _ := client.GasPrice()
+	tip, _ := client.MaxPriorityFeePerGas()
+	balance, _ := client.Balance(nonceTestAddress)
+	if gasPrice.Int64() != 20000000000 || tip.Int64() != 1000000000 || balance.String() != "1000000000000000000" {
+		t.Fatalf("gasPrice %s tip %s balance %s", gasPrice, tip, balance)
+	}
+	if _, err := client.PendingNonce(big.NewInt(56), nonceTestAddress); nil == err {
+		t.Fatal("expected chain id mismatch")
+	}
+
+	var rpcErr *RpcError
+	if err := client.Call("eth_unknown", nil); !errors.As(err, &rpcErr) || rpcErr.Code != -32601 {
+		t.Fatalf("expected rpc error, got %v", err)
+	}
+}
+
+func TestSuggestFee(t *testing.T) {
+	_, client := newFakeNode(t)
+	suggestion, err := SuggestFee(client)
+	if nil != err {
+		t.Fatal(err)
+	}
+	// 下一区块 baseFee 0x82=130，各档小费取中位数 2/6/20
+	if suggestion.BaseFee.Int64() != 130 ||
+		suggestion.Slow.GasTipCap.Int64() != 2 || suggestion.Slow.GasFeeCap.Int64() != 262 ||
+		suggestion.Standard.GasTipCap.Int64() != 6 ||
+		suggestion.Fast.GasTipCap.Int64() != 20 || suggestion.Fast.GasFeeCap.Int64() != 280 {
+		t.Fatalf("suggestion %+v %+v %+v", suggestion.Slow, suggestion.Standard, suggestion.Fast)
+	}
+}
+
+// 完整流程：NonceManager 以 Client 为 nonce 来源，签名广播后轮询回执
+func TestClientSendAndWait(t *testing.T) {
+	node, client := newFakeNode(t)
+	privateKey, err := hdWallet.GetInstanceByHDWalletUtil().LoadWalletByPrivateKey("4646464646464646464646464646464646464646464646464646464646464646")
+	if nil != err {
+		t.Fatal(err)
+	}
+	from := PrivateKeyToAddressETH(privateKey)
+	chainId := big.NewInt(1)
+	manager := NewNonceManager(client)
+	suggestion, err := SuggestFee(client)
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		nonce, err := manager.Reserve(chainId, from)
+		if nil != err {
+			t.Fatal(err)
+		}
+		req := &TxRequest{
+			ChainId:   chainId,
+			Type:      DynamicFeeTxType,
+			Nonce:     nonce,
+			To:        nonceTestAddress,
+			Value:     big.NewInt(1),
+			Gas:       21000,
+			GasTipCap: suggestion.Standard.GasTipCap,
+			GasFeeCap: suggestion.Standard.GasFeeCap,
+		}
+		tx, _ := BuildTx(req)
+		signed, err := SignTransaction(tx, chainId, privateKey)
+		if nil != err {
+			t.Fatal(err)
+		}
+		txHash, err := client.SendRawTransaction(signed.RawTx)
+		if nil != err {
+			manager.Release(chainId, from, nonce)
+			t.Fatal(err)
+		}
+		if txHash != signed.TxHash {
+			t.Fatalf("tx hash mismatch %s %s", txHash, signed.TxHash)
+		}
+		if err := manager.Commit(chainId, from, signed.Tx); nil != err {
+			t.Fatal(err)
+		}
+		receipt, err := WaitReceipt(client, txHash, time.Millisecond, time.Second)
+		if nil != err {
+			t.Fatal(err)
+		}
+		if receipt.Status != 1 || receipt.TxHash.Hex() != txHash || manager.Pending(chainId, from)[i].TxHash != txHash {
+			t.Fatalf("receipt %+v", receipt)
+		}
+	}
+	if len(node.sent) != 2 || node.nonce != 5 {
+		t.Fatalf("node state sent %d nonce %d", len(node.sent), node.nonce)
+	}
+}
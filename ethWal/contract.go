@@ -0,0 +1,103 @@
+package ethWal
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"github.com/PandaManPMC/txBuilder/evmAbi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// CreateAddress CREATE 部署的合约地址：keccak256(rlp([sender, nonce]))[12:]
+func CreateAddress(sender string, nonce uint64) (string, error) {
+	if !ValidAddress(sender) {
+		return "", fmt.Errorf("invalid sender address: %s", sender)
+	}
+	address := crypto.CreateAddress(common.HexToAddress(sender), nonce)
+	return checksumAddress(address.Bytes()), nil
+}
+
+// Create2Address CREATE2 部署的合约地址：keccak256(0xff ++ deployer ++ salt ++ keccak256(initCode))[12:]，
+// initCode 为创建字节码加构造参数
+func Create2Address(deployer string, salt [32]byte, initCode []byte) (string, error) {
+	return Create2AddressByHash(deployer, salt, crypto.Keccak256(initCode))
+}
+
+// Create2AddressByHash 已知 initCode 哈希时计算 CREATE2 地址，工厂合约一般直接给出该哈希
+func Create2AddressByHash(deployer string, salt [32]byte, initCodeHash []byte) (string, error) {
+	if !ValidAddress(deployer) {
+		return "", fmt.Errorf("invalid deployer address: %s", deployer)
+	}
+	if len(initCodeHash) != 32 {
+		return "", fmt.Errorf("invalid init code hash length: %d", len(initCodeHash))
+	}
+	address := crypto.CreateAddress2(common.HexToAddress(deployer), salt, initCodeHash)
+	return checksumAddress(address.Bytes()), nil
+}
+
+// DeployData 部署交易的 data：创建字节码 ++ ABI 编码的构造参数，constructorTypes 如 []string{"address", "uint256"}
+func DeployData(bytecode []byte, constructorTypes []string, args ...interface{}) ([]byte, error) {
+	if len(bytecode) == 0 {
+		return nil, errors.New("bytecode miss")
+	}
+	data := make([]byte, len(bytecode))
+	copy(data, bytecode)
+	if len(constructorTypes) == 0 {
+		return data, nil
+	}
+	encoded, err := evmAbi.EncodeArgs(constructorTypes, args...)
+	if nil != err {
+		return nil, fmt.Errorf("encode constructor args error: %v", err)
+	}
+	return append(data, encoded...), nil
+}
+
+// DeployRequest 合约部署参数，Tx.To 必须为空，Tx.Data 由 Bytecode 与构造参数生成
+type DeployRequest struct {
+	Tx               *TxRequest    `json:"tx"`
+	Bytecode         []byte        `json:"bytecode"`
+	ConstructorTypes []string      `json:"constructorTypes"`
+	ConstructorArgs  []interface{} `json:"constructorArgs"`
+}
+
+// DeployTx 签名后的部署交易，ContractAddress 为按发送者与 nonce 计算的合约地址
+type DeployTx struct {
+	*SignedTx
+	ContractAddress string `json:"contractAddress"`
+}
+
+// BuildDeployTx 构建未签名的部署交易
+func BuildDeployTx(req *DeployRequest) (*types.Transaction, error) {
+	if req.Tx == nil {
+		return nil, errors.New("deploy tx request miss")
+	}
+	if req.Tx.To != "" {
+		return nil, errors.New("deploy tx must not set to address")
+	}
+	data, err := DeployData(req.Bytecode, req.ConstructorTypes, req.ConstructorArgs...)
+	if nil != err {
+		return nil, err
+	}
+	txReq := *req.Tx
+	txReq.Data = data
+	return BuildTx(&txReq)
+}
+
+// SignDeployTx 构建并签名部署交易
+func SignDeployTx(req *DeployRequest, privateKey *ecdsa.PrivateKey) (*DeployTx, error) {
+	tx, err := BuildDeployTx(req)
+	if nil != err {
+		return nil, err
+	}
+	signed, err := SignTransaction(tx, req.Tx.ChainId, privateKey)
+	if nil != err {
+		return nil, err
+	}
+	contractAddress, err := CreateAddress(signed.From, tx.Nonce())
+	if nil != err {
+		return nil, err
+	}
+	return &DeployTx{SignedTx: signed, ContractAddress: contractAddress}, nil
+}
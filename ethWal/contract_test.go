@@ -0,0 +1,96 @@
+package ethWal
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestCreateAddress(t *testing.T) {
+	for nonce, expect := range []string{
+		"0xcd234a471b72ba2f1ccf0a70fcaba648a5eecd8d",
+		"0x343c43a37d37dff08ae8c4a11544c718abb4fcf8",
+	} {
+		address, err := CreateAddress("0x6ac7ea33f8831ea9dcc53393aaa88b25a785dbf0", uint64(nonce))
+		if nil != err {
+			t.Fatal(err)
+		}
+		if !strings.EqualFold(address, expect) || address != ChecksumAddress(expect) {
+			t.Fatalf("nonce %d address %s", nonce, address)
+		}
+	}
+}
+
+// EIP-1014 规范中的示例
+func TestCreate2Address(t *testing.T) {
+	cases := []struct {
+		deployer string
+		salt     string
+		initCode string
+		expect   string
+	}{
+		{"0x0000000000000000000000000000000000000000", "0x0000000000000000000000000000000000000000000000000000000000000000", "0x00", "0x4D1A2e2bB4F88F0250f26Ffff098B0b30B26BF38"},
+		{"0xdeadbeef00000000000000000000000000000000", "0x000000000000000000000000feed000000000000000000000000000000000000", "0x00", "0xD04116cDd17beBE565EB2422F2497E06cC1C9833"},
+		{"0x00000000000000000000000000000000deadbeef", "0x00000000000000000000000000000000000000000000000000000000cafebabe", "0xdeadbeef", "0x60f3f640a8508fC6a86d45DF051962668E1e8AC7"},
+		{"0x0000000000000000000000000000000000000000", "0x0000000000000000000000000000000000000000000000000000000000000000", "0x", "0xE33C0C7F7df4809055C3ebA6c09CFe4BaF1BD9e0"},
+	}
+	for _, c := range cases {
+		var salt [32]byte
+		copy(salt[:], hexutil.MustDecode(c.salt))
+		address, err := Create2Address(c.deployer, salt, hexutil.MustDecode(c.initCode))
+		if nil != err {
+			t.Fatal(err)
+		}
+		if address != c.expect {
+			t.Fatalf("create2 address %s expect %s", address, c.expect)
+		}
+	}
+}
+
+func TestSignDeployTx(t *testing.T) {
+	privateKey, from, err := ImportWallet("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", 0)
+	if nil != err {
+		t.Fatal(err)
+	}
+	bytecode := hexutil.MustDecode("0x6080604052348015600e575f5ffd5b50")
+	owner := "0x3535353535353535353535353535353535353535"
+	deployTx, err := SignDeployTx(&DeployRequest{
+		Tx: &TxRequest{
+			ChainId:   big.NewInt(1),
+			Type:      DynamicFeeTxType,
+			Nonce:     7,
+			Gas:       500000,
+			GasTipCap: big.NewInt(1000000000),
+			GasFeeCap: big.NewInt(30000000000),
+		},
+		Bytecode:         bytecode,
+		ConstructorTypes: []string{"address", "uint256"},
+		ConstructorArgs:  []interface{}{owner, big.NewInt(100)},
+	}, privateKey)
+	if nil != err {
+		t.Fatal(err)
+	}
+	expectAddress, _ := CreateAddress(from, 7)
+	if deployTx.ContractAddress != expectAddress {
+		t.Fatalf("contract address %s expect %s", deployTx.ContractAddress, expectAddress)
+	}
+
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(hexutil.MustDecode(deployTx.RawTx)); nil != err {
+		t.Fatal(err)
+	}
+	if tx.To() != nil {
+		t.Fatal("deploy tx should not have to address")
+	}
+	data := tx.Data()
+	if len(data) != len(bytecode)+64 || common.BytesToAddress(data[len(bytecode):len(bytecode)+32]) != common.HexToAddress(owner) {
+		t.Fatalf("deploy data %x", data)
+	}
+
+	if _, err := BuildDeployTx(&DeployRequest{Tx: &TxRequest{To: owner}, Bytecode: bytecode}); nil == err {
+		t.Fatal("deploy tx with to address should fail")
+	}
+}
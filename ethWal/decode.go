@@ -0,0 +1,107 @@
+package ethWal
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/PandaManPMC/txBuilder/evmAbi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+)
+
+// DecodedTx 解码后的已签名交易，数值均为十进制字符串，不适用于该交易类型的字段为空
+type DecodedTx struct {
+	Type       uint8            `json:"type"`
+	Hash       string           `json:"hash"`
+	ChainId    string           `json:"chainId"`
+	Protected  bool             `json:"protected"`
+	From       string           `json:"from"`
+	To         string           `json:"to"`
+	Nonce      uint64           `json:"nonce"`
+	Value      string           `json:"value"`
+	Gas        uint64           `json:"gas"`
+	GasPrice   string           `json:"gasPrice,omitempty"`
+	GasTipCap  string           `json:"maxPriorityFeePerGas,omitempty"`
+	GasFeeCap  string           `json:"maxFeePerGas,omitempty"`
+	Data       string           `json:"data"`
+	AccessList types.AccessList `json:"accessList,omitempty"`
+	V          string           `json:"v"`
+	R          string           `json:"r"`
+	S          string           `json:"s"`
+	// TokenCall data 为 ERC-20/721/1155 转账、授权调用时的解析结果
+	TokenCall *evmAbi.TokenCall `json:"tokenCall,omitempty"`
+}
+
+// DecodeRawTx 解码已签名的 type 0/1/2 交易并恢复发送者。
+// chainId 不为空时校验交易的 chainId，未做 EIP-155 重放保护的 legacy 交易视为不匹配
+func DecodeRawTx(rawTx string, chainId *big.Int) (*DecodedTx, error) {
+	raw, err := hexutil.Decode(rawTx)
+	if nil != err {
+		return nil, fmt.Errorf("decode raw tx hex error: %v", err)
+	}
+	tx := new(types.Transaction)
+	if err = tx.UnmarshalBinary(raw); nil != err {
+		return nil, fmt.Errorf("unmarshal raw tx error: %v", err)
+	}
+
+	var signer types.Signer
+	if tx.Protected() {
+		signer = types.LatestSignerForChainID(tx.ChainId())
+	} else {
+		signer = types.HomesteadSigner{}
+	}
+	if chainId != nil && (!tx.Protected() || tx.ChainId().Cmp(chainId) != 0) {
+		return nil, fmt.Errorf("chain id mismatch: tx %s, expect %s", tx.ChainId(), chainId)
+	}
+	from, err := types.Sender(signer, tx)
+	if nil != err {
+		return nil, fmt.Errorf("recover sender error: %v", err)
+	}
+
+	v, r, s := tx.RawSignatureValues()
+	decoded := &DecodedTx{
+		Type:      tx.Type(),
+		Hash:      tx.Hash().Hex(),
+		ChainId:   tx.ChainId().String(),
+		Protected: tx.Protected(),
+		From:      checksumAddress(from.Bytes()),
+		Nonce:     tx.Nonce(),
+		Value:     tx.Value().String(),
+		Gas:       tx.Gas(),
+		Data:      hexutil.Encode(tx.Data()),
+		V:         v.String(),
+		R:         hexutil.EncodeBig(r),
+		S:         hexutil.EncodeBig(s),
+	}
+	if tx.To() != nil {
+		decoded.To = checksumAddress(tx.To().Bytes())
+	}
+	if tx.Type() == DynamicFeeTxType {
+		decoded.GasTipCap = tx.GasTipCap().String()
+		decoded.GasFeeCap = tx.GasFeeCap().String()
+	} else {
+		decoded.GasPrice = tx.GasPrice().String()
+	}
+	if tx.Type() != LegacyTxType {
+		decoded.AccessList = tx.AccessList()
+	}
+	if len(tx.Data()) >= 4 {
+		if call, err := evmAbi.DecodeTokenCall(tx.Data()); nil == err {
+			decoded.TokenCall = call
+		}
+	}
+	return decoded, nil
+}
+
+// DecodeRawTxJson DecodeRawTx 的 JSON 输出
+func DecodeRawTxJson(rawTx string, chainId *big.Int) (string, error) {
+	decoded, err := DecodeRawTx(rawTx, chainId)
+	if nil != err {
+		return "", err
+	}
+	b, err := json.Marshal(decoded)
+	if nil != err {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,56 @@
+package ethWal
+
+import (
+	"github.com/PandaManPMC/txBuilder/evmAbi"
+	"github.com/PandaManPMC/txBuilder/hdWallet"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestDecodeRawTx(t *testing.T) {
+	// EIP-155 规范中的示例，发送者 0x9d8A62f656a8d1615C1294fd71e9CFb3E4855A4F
+	raw := "0xf86c098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a76400008025a028ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276a067cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83"
+	decoded, err := DecodeRawTx(raw, big.NewInt(1))
+	if nil != err {
+		t.Fatal(err)
+	}
+	if decoded.From != "0x9d8A62f656a8d1615C1294fd71e9CFb3E4855A4F" || decoded.Nonce != 9 || decoded.Value != "1000000000000000000" ||
+		decoded.GasPrice != "20000000000" || decoded.V != "37" || !decoded.Protected {
+		t.Fatalf("decoded %+v", decoded)
+	}
+	if _, err := DecodeRawTx(raw, big.NewInt(56)); nil == err || !strings.Contains(err.Error(), "chain id mismatch") {
+		t.Fatalf("expected chain id mismatch, got %v", err)
+	}
+}
+
+func TestDecodeRawTxTyped(t *testing.T) {
+	privateKey, err := hdWallet.GetInstanceByHDWalletUtil().LoadWalletByPrivateKey("1ea107cf1e8cbca5a1e9ee2661505b1836db495c574eace64ecdbc20b29b83fd")
+	if nil != err {
+		t.Fatal(err)
+	}
+	data, _ := evmAbi.ERC20Transfer("0x3535353535353535353535353535353535353535", big.NewInt(5000))
+	for _, req := range []*TxRequest{
+		{ChainId: big.NewInt(137), Type: AccessListTxType, Nonce: 1, To: "0xdAC17F958D2ee523a2206206994597C13D831ec7", Gas: 60000, GasPrice: big.NewInt(30000000000), Data: data},
+		{ChainId: big.NewInt(137), Type: DynamicFeeTxType, Nonce: 2, To: "0xdAC17F958D2ee523a2206206994597C13D831ec7", Gas: 60000, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2), Data: data},
+	} {
+		signed, err := SignTx(req, privateKey)
+		if nil != err {
+			t.Fatal(err)
+		}
+		decoded, err := DecodeRawTx(signed.RawTx, big.NewInt(137))
+		if nil != err {
+			t.Fatal(err)
+		}
+		if decoded.Type != req.Type || decoded.Hash != signed.TxHash || decoded.From != signed.From || decoded.To != req.To || decoded.ChainId != "137" {
+			t.Fatalf("decoded %+v", decoded)
+		}
+		if decoded.TokenCall == nil || decoded.TokenCall.Amount.Int64() != 5000 {
+			t.Fatalf("token call %+v", decoded.TokenCall)
+		}
+	}
+
+	if _, err := DecodeRawTxJson("0x1234", nil); nil == err {
+		t.Fatal("expected decode error")
+	}
+}
@@ -0,0 +1,336 @@
+package ethWal
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"math/big"
+	"strings"
+)
+
+// PersonalMessageHash EIP-191 personal_sign 摘要：keccak256("\x19Ethereum Signed Message:\n" + len(message) + message)
+func PersonalMessageHash(message []byte) []byte {
+	return accounts.TextHash(message)
+}
+
+// PersonalSign personal_sign 签名，返回 65 字节 r||s||v 的 hex，v 为 27/28，与钱包输出一致
+func PersonalSign(message []byte, privateKey *ecdsa.PrivateKey) (string, error) {
+	return signHash(PersonalMessageHash(message), privateKey)
+}
+
+// RecoverPersonalSign 从 personal_sign 签名恢复签名地址
+func RecoverPersonalSign(message []byte, signature string) (string, error) {
+	return RecoverAddress(PersonalMessageHash(message), signature)
+}
+
+// VerifyPersonalSign 校验 personal_sign 签名是否由 address 签出
+func VerifyPersonalSign(message []byte, signature, address string) (bool, error) {
+	recovered, err := RecoverPersonalSign(message, signature)
+	if nil != err {
+		return false, err
+	}
+	return strings.EqualFold(recovered, address), nil
+}
+
+// TypedDataHash EIP-712 摘要：keccak256("\x19\x01" || domainSeparator || hashStruct(message))，
+// typedData 为 eth_signTypedData_v4 使用的 JSON 文档
+func TypedDataHash(typedData []byte) ([]byte, error) {
+	var data apitypes.TypedData
+	if err := json.Unmarshal(typedData, &data); nil != err {
+		return nil, fmt.Errorf("unmarshal typed data error: %v", err)
+	}
+	return hashTypedData(data)
+}
+
+// SignTypedData eth_signTypedData_v4 签名
+func SignTypedData(typedData []byte, privateKey *ecdsa.PrivateKey) (string, error) {
+	hash, err := TypedDataHash(typedData)
+	if nil != err {
+		return "", err
+	}
+	return signHash(hash, privateKey)
+}
+
+// RecoverTypedData 从 EIP-712 签名恢复签名地址
+func RecoverTypedData(typedData []byte, signature string) (string, error) {
+	hash, err := TypedDataHash(typedData)
+	if nil != err {
+		return "", err
+	}
+	return RecoverAddress(hash, signature)
+}
+
+// RecoverAddress 从 32 字节摘要和 65 字节签名恢复地址，v 支持 0/1 和 27/28
+func RecoverAddress(hash []byte, signature string) (string, error) {
+	sig, err := hexutil.Decode(signature)
+	if nil != err {
+		return "", fmt.Errorf("decode signature error: %v", err)
+	}
+	if len(sig) != crypto.SignatureLength {
+		return "", fmt.Errorf("invalid signature length: %d", len(sig))
+	}
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+	if sig[crypto.RecoveryIDOffset] > 1 {
+		return "", errors.New("invalid signature recovery id")
+	}
+	pubKey, err := crypto.SigToPub(hash, sig)
+	if nil != err {
+		return "", fmt.Errorf("recover public key error: %v", err)
+	}
+	return PubKeyToAddressETH(*pubKey), nil
+}
+
+func signHash(hash []byte, privateKey *ecdsa.PrivateKey) (string, error) {
+	sig, err := crypto.Sign(hash, privateKey)
+	if nil != err {
+		return "", fmt.Errorf("sign error: %v", err)
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+	return hexutil.Encode(sig), nil
+}
+
+func hashTypedData(data apitypes.TypedData) ([]byte, error) {
+	hash, _, err := apitypes.TypedDataAndHash(data)
+	if nil != err {
+		return nil, fmt.Errorf("hash typed data error: %v", err)
+	}
+	return hash, nil
+}
+
+// Permit2Address Uniswap Permit2 在各 EVM 链上的统一部署地址
+const Permit2Address = "0x000000000022D473030F116dDEE9F6B43aC78BA3"
+
+// PermitSignature 拆分后的签名，可直接作为 permit(owner, spender, value, deadline, v, r, s) 参数
+type PermitSignature struct {
+	Signature string `json:"signature"`
+	Hash      string `json:"hash"`
+	V         uint8  `json:"v"`
+	R         string `json:"r"`
+	S         string `json:"s"`
+}
+
+// PermitRequest ERC-2612 permit 参数，TokenName/Version 须与代币合约 DOMAIN_SEPARATOR 一致，Version 为空时使用 "1"
+type PermitRequest struct {
+	ChainId   *big.Int `json:"chainId"`
+	Token     string   `json:"token"`
+	TokenName string   `json:"tokenName"`
+	Version   string   `json:"version"`
+	Owner     string   `json:"owner"`
+	Spender   string   `json:"spender"`
+	Value     *big.Int `json:"value"`
+	Nonce     *big.Int `json:"nonce"`
+	Deadline  *big.Int `json:"deadline"`
+}
+
+// PermitTypedData ERC-2612 permit 的 EIP-712 文档
+func PermitTypedData(req *PermitRequest) (apitypes.TypedData, error) {
+	if req.ChainId == nil || req.Value == nil || req.Nonce == nil || req.Deadline == nil {
+		return apitypes.TypedData{}, errors.New("permit miss chainId, value, nonce or deadline")
+	}
+	for _, address := range []string{req.Token, req.Owner, req.Spender} {
+		if !ValidAddress(address) {
+			return apitypes.TypedData{}, fmt.Errorf("invalid address: %s", address)
+		}
+	}
+	version := req.Version
+	if version == "" {
+		version = "1"
+	}
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"Permit": {
+				{Name: "owner", Type: "address"},
+				{Name: "spender", Type: "address"},
+				{Name: "value", Type: "uint256"},
+				{Name: "nonce", Type: "uint256"},
+				{Name: "deadline", Type: "uint256"},
+			},
+		},
+		PrimaryType: "Permit",
+		Domain: apitypes.TypedDataDomain{
+			Name:              req.TokenName,
+			Version:           version,
+			ChainId:           (*math.HexOrDecimal256)(req.ChainId),
+			VerifyingContract: req.Token,
+		},
+		Message: apitypes.TypedDataMessage{
+			"owner":    req.Owner,
+			"spender":  req.Spender,
+			"value":    req.Value,
+			"nonce":    req.Nonce,
+			"deadline": req.Deadline,
+		},
+	}, nil
+}
+
+// SignPermit 签名 ERC-2612 permit
+func SignPermit(req *PermitRequest, privateKey *ecdsa.PrivateKey) (*PermitSignature, error) {
+	data, err := PermitTypedData(req)
+	if nil != err {
+		return nil, err
+	}
+	return signTypedDataSplit(data, privateKey)
+}
+
+// Permit2TransferRequest Permit2 SignatureTransfer 的 PermitTransferFrom，Spender 为调用 permitTransferFrom 的合约
+type Permit2TransferRequest struct {
+	ChainId  *big.Int `json:"chainId"`
+	Token    string   `json:"token"`
+	Amount   *big.Int `json:"amount"`
+	Spender  string   `json:"spender"`
+	Nonce    *big.Int `json:"nonce"`
+	Deadline *big.Int `json:"deadline"`
+}
+
+// Permit2TransferTypedData Permit2 PermitTransferFrom 的 EIP-712 文档
+func Permit2TransferTypedData(req *Permit2TransferRequest) (apitypes.TypedData, error) {
+	if req.ChainId == nil || req.Amount == nil || req.Nonce == nil || req.Deadline == nil {
+		return apitypes.TypedData{}, errors.New("permit2 miss chainId, amount, nonce or deadline")
+	}
+	if !ValidAddress(req.Token) || !ValidAddress(req.Spender) {
+		return apitypes.TypedData{}, errors.New("invalid token or spender address")
+	}
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": permit2DomainType(),
+			"PermitTransferFrom": {
+				{Name: "permitted", Type: "TokenPermissions"},
+				{Name: "spender", Type: "address"},
+				{Name: "nonce", Type: "uint256"},
+				{Name: "deadline", Type: "uint256"},
+			},
+			"TokenPermissions": {
+				{Name: "token", Type: "address"},
+				{Name: "amount", Type: "uint256"},
+			},
+		},
+		PrimaryType: "PermitTransferFrom",
+		Domain:      permit2Domain(req.ChainId),
+		Message: apitypes.TypedDataMessage{
+			"permitted": map[string]interface{}{
+				"token":  req.Token,
+				"amount": req.Amount,
+			},
+			"spender":  req.Spender,
+			"nonce":    req.Nonce,
+			"deadline": req.Deadline,
+		},
+	}, nil
+}
+
+// SignPermit2Transfer 签名 Permit2 PermitTransferFrom
+func SignPermit2Transfer(req *Permit2TransferRequest, privateKey *ecdsa.PrivateKey) (*PermitSignature, error) {
+	data, err := Permit2TransferTypedData(req)
+	if nil != err {
+		return nil, err
+	}
+	return signTypedDataSplit(data, privateKey)
+}
+
+// Permit2AllowanceRequest Permit2 AllowanceTransfer 的 PermitSingle，Amount 为 uint160，Expiration/Nonce 为 uint48
+type Permit2AllowanceRequest struct {
+	ChainId     *big.Int `json:"chainId"`
+	Token       string   `json:"token"`
+	Amount      *big.Int `json:"amount"`
+	Expiration  uint64   `json:"expiration"`
+	Nonce       uint64   `json:"nonce"`
+	Spender     string   `json:"spender"`
+	SigDeadline *big.Int `json:"sigDeadline"`
+}
+
+// Permit2AllowanceTypedData Permit2 PermitSingle 的 EIP-712 文档
+func Permit2AllowanceTypedData(req *Permit2AllowanceRequest) (apitypes.TypedData, error) {
+	if req.ChainId == nil || req.Amount == nil || req.SigDeadline == nil {
+		return apitypes.TypedData{}, errors.New("permit2 miss chainId, amount or sigDeadline")
+	}
+	if !ValidAddress(req.Token) || !ValidAddress(req.Spender) {
+		return apitypes.TypedData{}, errors.New("invalid token or spender address")
+	}
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": permit2DomainType(),
+			"PermitSingle": {
+				{Name: "details", Type: "PermitDetails"},
+				{Name: "spender", Type: "address"},
+				{Name: "sigDeadline", Type: "uint256"},
+			},
+			"PermitDetails": {
+				{Name: "token", Type: "address"},
+				{Name: "amount", Type: "uint160"},
+				{Name: "expiration", Type: "uint48"},
+				{Name: "nonce", Type: "uint48"},
+			},
+		},
+		PrimaryType: "PermitSingle",
+		Domain:      permit2Domain(req.ChainId),
+		Message: apitypes.TypedDataMessage{
+			"details": map[string]interface{}{
+				"token":      req.Token,
+				"amount":     req.Amount,
+				"expiration": new(big.Int).SetUint64(req.Expiration),
+				"nonce":      new(big.Int).SetUint64(req.Nonce),
+			},
+			"spender":     req.Spender,
+			"sigDeadline": req.SigDeadline,
+		},
+	}, nil
+}
+
+// SignPermit2Allowance 签名 Permit2 PermitSingle
+func SignPermit2Allowance(req *Permit2AllowanceRequest, privateKey *ecdsa.PrivateKey) (*PermitSignature, error) {
+	data, err := Permit2AllowanceTypedData(req)
+	if nil != err {
+		return nil, err
+	}
+	return signTypedDataSplit(data, privateKey)
+}
+
+// Permit2 的 domain 没有 version
+func permit2DomainType() []apitypes.Type {
+	return []apitypes.Type{
+		{Name: "name", Type: "string"},
+		{Name: "chainId", Type: "uint256"},
+		{Name: "verifyingContract", Type: "address"},
+	}
+}
+
+func permit2Domain(chainId *big.Int) apitypes.TypedDataDomain {
+	return apitypes.TypedDataDomain{
+		Name:              "Permit2",
+		ChainId:           (*math.HexOrDecimal256)(chainId),
+		VerifyingContract: Permit2Address,
+	}
+}
+
+func signTypedDataSplit(data apitypes.TypedData, privateKey *ecdsa.PrivateKey) (*PermitSignature, error) {
+	hash, err := hashTypedData(data)
+	if nil != err {
+		return nil, err
+	}
+	signature, err := signHash(hash, privateKey)
+	if nil != err {
+		return nil, err
+	}
+	sig := hexutil.MustDecode(signature)
+	return &PermitSignature{
+		Signature: signature,
+		Hash:      hexutil.Encode(hash),
+		V:         sig[crypto.RecoveryIDOffset],
+		R:         hexutil.Encode(sig[:32]),
+		S:         hexutil.Encode(sig[32:64]),
+	}, nil
+}
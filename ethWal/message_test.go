@@ -0,0 +1,202 @@
+package ethWal
+
+import (
+	"github.com/PandaManPMC/txBuilder/hdWallet"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// EIP-712 规范中的 Mail 示例，私钥为 keccak256("cow")
+const mailTypedData = `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "version", "type": "string"},
+			{"name": "chainId", "type": "uint256"},
+			{"name": "verifyingContract", "type": "address"}
+		],
+		"Person": [
+			{"name": "name", "type": "string"},
+			{"name": "wallet", "type": "address"}
+		],
+		"Mail": [
+			{"name": "from", "type": "Person"},
+			{"name": "to", "type": "Person"},
+			{"name": "contents", "type": "string"}
+		]
+	},
+	"primaryType": "Mail",
+	"domain": {
+		"name": "Ether Mail",
+		"version": "1",
+		"chainId": 1,
+		"verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"
+	},
+	"message": {
+		"from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+		"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+		"contents": "Hello, Bob!"
+	}
+}`
+
+func TestSignTypedData(t *testing.T) {
+	privateKey, err := hdWallet.GetInstanceByHDWalletUtil().LoadWalletByPrivateKey("c85ef7d79691fe79573b1a7064c19c1a9819ebdbd1faaab1a8ec92344438aaf4")
+	if nil != err {
+		t.Fatal(err)
+	}
+	hash, err := TypedDataHash([]byte(mailTypedData))
+	if nil != err {
+		t.Fatal(err)
+	}
+	if hexString(hash) != "be609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2" {
+		t.Fatalf("typed data hash mismatch %x", hash)
+	}
+	signature, err := SignTypedData([]byte(mailTypedData), privateKey)
+	if nil != err {
+		t.Fatal(err)
+	}
+	expect := "0x4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b915621c"
+	if signature != expect {
+		t.Fatalf("signature mismatch %s", signature)
+	}
+	address, err := RecoverTypedData([]byte(mailTypedData), signature)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if !strings.EqualFold(address, "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826") {
+		t.Fatalf("recover mismatch %s", address)
+	}
+}
+
+func TestPersonalSign(t *testing.T) {
+	privateKey, address, err := ImportWallet("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", 0)
+	if nil != err {
+		t.Fatal(err)
+	}
+	message := []byte("Sign in to example.com\nNonce: 42")
+	signature, err := PersonalSign(message, privateKey)
+	if nil != err {
+		t.Fatal(err)
+	}
+	ok, err := VerifyPersonalSign(message, signature, address)
+	if nil != err || !ok {
+		t.Fatalf("verify failed %v", err)
+	}
+	ok, _ = VerifyPersonalSign([]byte("other"), signature, address)
+	if ok {
+		t.Fatal("verify other message should fail")
+	}
+}
+
+func TestSignPermit(t *testing.T) {
+	privateKey, address, err := ImportWallet("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", 0)
+	if nil != err {
+		t.Fatal(err)
+	}
+	permit, err := SignPermit(&PermitRequest{
+		ChainId:   big.NewInt(1),
+		Token:     "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+		TokenName: "USD Coin",
+		Version:   "2",
+		Owner:     address,
+		Spender:   "0x3535353535353535353535353535353535353535",
+		Value:     big.NewInt(1000000),
+		Nonce:     big.NewInt(0),
+		Deadline:  big.NewInt(1900000000),
+	}, privateKey)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if permit.V != 27 && permit.V != 28 {
+		t.Fatalf("invalid v %d", permit.V)
+	}
+	if !strings.EqualFold(address, "0x9858EfFD232B4033E47d90003D41EC34EcaEda94") {
+		t.Fatalf("address %s", address)
+	}
+	// USDC 主网合约 DOMAIN_SEPARATOR() 与 OpenZeppelin ERC20Permit 的 PERMIT_TYPEHASH
+	data, _ := PermitTypedData(&PermitRequest{ChainId: big.NewInt(1), Token: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", TokenName: "USD Coin", Version: "2",
+		Owner: address, Spender: "0x3535353535353535353535353535353535353535", Value: big.NewInt(1000000), Nonce: big.NewInt(0), Deadline: big.NewInt(1900000000)})
+	checkTypedDataHashes(t, data,
+		"06c37168a7db5138defc7866392bb87a741f9b3d104deb5094588ce041cae335",
+		"6e71edae12b1b97f4d1f60370fef10105fa2faae0126114a169c64845d6126c9")
+	if permit.Hash != "0xd27d9585ce5f0fd2285262a955c2f7e0eda8b2e0c5ec3d09358465b93b9a3b8d" {
+		t.Fatalf("permit hash %s", permit.Hash)
+	}
+	recovered, err := RecoverAddress(hexDecode(permit.Hash), permit.Signature)
+	if nil != err || !strings.EqualFold(recovered, address) {
+		t.Fatalf("recover mismatch %s %v", recovered, err)
+	}
+
+	permit2, err := SignPermit2Transfer(&Permit2TransferRequest{
+		ChainId:  big.NewInt(1),
+		Token:    "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+		Amount:   big.NewInt(1000000),
+		Spender:  "0x3535353535353535353535353535353535353535",
+		Nonce:    big.NewInt(1),
+		Deadline: big.NewInt(1900000000),
+	}, privateKey)
+	if nil != err {
+		t.Fatal(err)
+	}
+	recovered, _ = RecoverAddress(hexDecode(permit2.Hash), permit2.Signature)
+	if !strings.EqualFold(recovered, address) {
+		t.Fatalf("permit2 recover mismatch %s", recovered)
+	}
+	// Permit2 主网 DOMAIN_SEPARATOR()（domain 无 version）与 PermitHash 库的 _PERMIT_TRANSFER_FROM_TYPEHASH
+	data, _ = Permit2TransferTypedData(&Permit2TransferRequest{ChainId: big.NewInt(1), Token: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", Amount: big.NewInt(1000000),
+		Spender: "0x3535353535353535353535353535353535353535", Nonce: big.NewInt(1), Deadline: big.NewInt(1900000000)})
+	checkTypedDataHashes(t, data,
+		"866a5aba21966af95d6c7ab78eb2b2fc913915c28be3b9aa07cc04ff903e3f28",
+		"939c21a48a8dbe3a9a2404a1d46691e4d39f6583d6ec6b35714604c986d80106")
+	if permit2.Hash != "0x69985f20fbdfaedb94cb435b21d7ec23538f3a26630d40028c25e75f237dd1e2" {
+		t.Fatalf("permit2 transfer hash %s", permit2.Hash)
+	}
+
+	allowance, err := SignPermit2Allowance(&Permit2AllowanceRequest{
+		ChainId:     big.NewInt(1),
+		Token:       "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+		Amount:      big.NewInt(1000000),
+		Expiration:  1900000000,
+		Nonce:       0,
+		Spender:     "0x3535353535353535353535353535353535353535",
+		SigDeadline: big.NewInt(1900000000),
+	}, privateKey)
+	if nil != err {
+		t.Fatal(err)
+	}
+	// _PERMIT_SINGLE_TYPEHASH 包含 uint160/uint48 宽度
+	data, _ = Permit2AllowanceTypedData(&Permit2AllowanceRequest{ChainId: big.NewInt(1), Token: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", Amount: big.NewInt(1000000),
+		Expiration: 1900000000, Spender: "0x3535353535353535353535353535353535353535", SigDeadline: big.NewInt(1900000000)})
+	checkTypedDataHashes(t, data,
+		"866a5aba21966af95d6c7ab78eb2b2fc913915c28be3b9aa07cc04ff903e3f28",
+		"f3841cd1ff0085026a6327b620b67997ce40f282c88a8e905a7a5626e310f3d0")
+	if allowance.Hash != "0x88df84156156642b1b06689389e3cce48ae582221e401d492f0101a90c70d5b9" {
+		t.Fatalf("permit2 allowance hash %s", allowance.Hash)
+	}
+}
+
+// checkTypedDataHashes 对照链上合约常量校验 domain separator 与主类型的 typehash
+func checkTypedDataHashes(t *testing.T, data apitypes.TypedData, domainSeparator, typeHash string) {
+	t.Helper()
+	separator, err := data.HashStruct("EIP712Domain", data.Domain.Map())
+	if nil != err {
+		t.Fatal(err)
+	}
+	if hexString(separator) != domainSeparator {
+		t.Fatalf("%s domain separator %x", data.PrimaryType, []byte(separator))
+	}
+	if hexString(data.TypeHash(data.PrimaryType)) != typeHash {
+		t.Fatalf("%s typehash %x", data.PrimaryType, []byte(data.TypeHash(data.PrimaryType)))
+	}
+}
+
+func hexString(b []byte) string {
+	return strings.TrimPrefix(hexutil.Encode(b), "0x")
+}
+
+func hexDecode(s string) []byte {
+	return hexutil.MustDecode(s)
+}
@@ -0,0 +1,250 @@
+package ethWal
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrNonceNotReserved       = errors.New("nonce not reserved")
+	ErrNonceNotCommitted      = errors.New("nonce not committed")
+	ErrReplacementUnderpriced = errors.New("replacement transaction underpriced")
+)
+
+// DefaultReplacementPriceBump 节点默认要求替换交易的 gas 价格至少提高 10%
+const DefaultReplacementPriceBump = 10
+
+// NonceSource 链上 nonce 来源，一般为 eth_getTransactionCount(address, "pending")
+type NonceSource interface {
+	PendingNonce(chainId *big.Int, address string) (uint64, error)
+}
+
+// NonceSourceFunc 函数适配 NonceSource
+type NonceSourceFunc func(chainId *big.Int, address string) (uint64, error)
+
+func (f NonceSourceFunc) PendingNonce(chainId *big.Int, address string) (uint64, error) {
+	return f(chainId, address)
+}
+
+// PendingNonce 已广播未确认的交易
+type PendingNonce struct {
+	Nonce     uint64   `json:"nonce"`
+	TxHash    string   `json:"txHash"`
+	GasTipCap *big.Int `json:"maxPriorityFeePerGas"`
+	GasFeeCap *big.Int `json:"maxFeePerGas"`
+	// Replaced 被替换的次数
+	Replaced int `json:"replaced"`
+}
+
+// NonceManager 按 (chainId, address) 本地分配 nonce，并发安全。
+// 流程：Reserve 取 nonce -> 签名广播成功 Commit，失败 Release；交易卡住时用 Replace 以更高的费用重发同一 nonce
+type NonceManager struct {
+	source    NonceSource
+	PriceBump int64
+	lock      sync.Mutex
+	accounts  map[string]*nonceAccount
+}
+
+type nonceAccount struct {
+	lock      sync.Mutex
+	synced    bool
+	next      uint64
+	reserved  map[uint64]struct{}
+	released  []uint64
+	committed map[uint64]*PendingNonce
+}
+
+func NewNonceManager(source NonceSource) *NonceManager {
+	return &NonceManager{
+		source:    source,
+		PriceBump: DefaultReplacementPriceBump,
+		accounts:  make(map[string]*nonceAccount),
+	}
+}
+
+func (m *NonceManager) account(chainId *big.Int, address string) *nonceAccount {
+	key := fmt.Sprintf("%s:%s", chainId.String(), strings.ToLower(address))
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	account, ok := m.accounts[key]
+	if !ok {
+		account = &nonceAccount{
+			reserved:  make(map[uint64]struct{}),
+			committed: make(map[uint64]*PendingNonce),
+		}
+		m.accounts[key] = account
+	}
+	return account
+}
+
+// sync 以链上 pending nonce 为准：低于它的已提交 nonce 视为已上链，本地落后时跳到链上的值
+func (m *NonceManager) sync(account *nonceAccount, chainId *big.Int, address string) (uint64, error) {
+	chainNonce, err := m.source.PendingNonce(chainId, address)
+	if nil != err {
+		return 0, fmt.Errorf("fetch pending nonce error: %v", err)
+	}
+	for nonce := range account.committed {
+		if nonce < chainNonce {
+			delete(account.committed, nonce)
+		}
+	}
+	released := account.released[:0]
+	for _, nonce := range account.released {
+		if nonce >= chainNonce {
+			released = append(released, nonce)
+		}
+	}
+	account.released = released
+	if !account.synced || account.next < chainNonce {
+		account.next = chainNonce
+	}
+	account.synced = true
+	return chainNonce, nil
+}
+
+// Reserve 分配一个 nonce，优先复用 Release 归还的最小 nonce，避免留下空洞
+func (m *NonceManager) Reserve(chainId *big.Int, address string) (uint64, error) {
+	account := m.account(chainId, address)
+	account.lock.Lock()
+	defer account.lock.Unlock()
+	if !account.synced {
+		if _, err := m.sync(account, chainId, address); nil != err {
+			return 0, err
+		}
+	}
+	var nonce uint64
+	if len(account.released) > 0 {
+		nonce = account.released[0]
+		account.released = account.released[1:]
+	} else {
+		nonce = account.next
+		account.next++
+	}
+	account.reserved[nonce] = struct{}{}
+	return nonce, nil
+}
+
+// Commit 交易广播成功后提交 nonce，from 为发送地址，tx 为签名后的交易（SignedTx.Tx）。
+// chainId 须与 Reserve 时一致，未做 EIP-155 保护的 legacy 交易 tx.ChainId() 为 0，不能由交易推断
+func (m *NonceManager) Commit(chainId *big.Int, from string, tx *types.Transaction) error {
+	account := m.account(chainId, from)
+	account.lock.Lock()
+	defer account.lock.Unlock()
+	if _, ok := account.reserved[tx.Nonce()]; !ok {
+		return fmt.Errorf("nonce %d: %w", tx.Nonce(), ErrNonceNotReserved)
+	}
+	delete(account.reserved, tx.Nonce())
+	account.committed[tx.Nonce()] = &PendingNonce{
+		Nonce:     tx.Nonce(),
+		TxHash:    tx.Hash().Hex(),
+		GasTipCap: tx.GasTipCap(),
+		GasFeeCap: tx.GasFeeCap(),
+	}
+	return nil
+}
+
+// Release 签名或广播失败时归还 nonce，下次 Reserve 会优先使用
+func (m *NonceManager) Release(chainId *big.Int, address string, nonce uint64) error {
+	account := m.account(chainId, address)
+	account.lock.Lock()
+	defer account.lock.Unlock()
+	if _, ok := account.reserved[nonce]; !ok {
+		return fmt.Errorf("nonce %d: %w", nonce, ErrNonceNotReserved)
+	}
+	delete(account.reserved, nonce)
+	account.released = append(account.released, nonce)
+	sort.Slice(account.released, func(i, j int) bool { return account.released[i] < account.released[j] })
+	// 归还的是末尾的 nonce 时直接回退 next
+	for len(account.released) > 0 && account.released[len(account.released)-1]+1 == account.next {
+		account.released = account.released[:len(account.released)-1]
+		account.next--
+	}
+	return nil
+}
+
+// ReplacementFee 替换已提交交易所需的最低费用，legacy 交易两者相同即 gasPrice
+func (m *NonceManager) ReplacementFee(chainId *big.Int, address string, nonce uint64) (gasTipCap, gasFeeCap *big.Int, err error) {
+	account := m.account(chainId, address)
+	account.lock.Lock()
+	defer account.lock.Unlock()
+	pending, ok := account.committed[nonce]
+	if !ok {
+		return nil, nil, fmt.Errorf("nonce %d: %w", nonce, ErrNonceNotCommitted)
+	}
+	return m.bump(pending.GasTipCap), m.bump(pending.GasFeeCap), nil
+}
+
+// Replace 用同一 nonce、更高费用的已签名交易替换已提交的交易，费用提高不足 PriceBump% 时返回 ErrReplacementUnderpriced
+func (m *NonceManager) Replace(chainId *big.Int, from string, tx *types.Transaction) error {
+	account := m.account(chainId, from)
+	account.lock.Lock()
+	defer account.lock.Unlock()
+	pending, ok := account.committed[tx.Nonce()]
+	if !ok {
+		return fmt.Errorf("nonce %d: %w", tx.Nonce(), ErrNonceNotCommitted)
+	}
+	if tx.GasTipCap().Cmp(m.bump(pending.GasTipCap)) < 0 || tx.GasFeeCap().Cmp(m.bump(pending.GasFeeCap)) < 0 {
+		return fmt.Errorf("nonce %d: %w", tx.Nonce(), ErrReplacementUnderpriced)
+	}
+	pending.TxHash = tx.Hash().Hex()
+	pending.GasTipCap = tx.GasTipCap()
+	pending.GasFeeCap = tx.GasFeeCap()
+	pending.Replaced++
+	return nil
+}
+
+func (m *NonceManager) bump(fee *big.Int) *big.Int {
+	bumped := new(big.Int).Mul(fee, big.NewInt(100+m.PriceBump))
+	bumped.Add(bumped, big.NewInt(99))
+	return bumped.Div(bumped, big.NewInt(100))
+}
+
+// Gaps 与链上同步后，返回低于本地 next 但既未预留也未提交的 nonce。
+// 这些空洞会阻塞后续交易上链，需要尽快 Reserve 并发送（可发 0 金额自转账填补）
+func (m *NonceManager) Gaps(chainId *big.Int, address string) ([]uint64, error) {
+	account := m.account(chainId, address)
+	account.lock.Lock()
+	defer account.lock.Unlock()
+	chainNonce, err := m.sync(account, chainId, address)
+	if nil != err {
+		return nil, err
+	}
+	gaps := make([]uint64, 0)
+	for nonce := chainNonce; nonce < account.next; nonce++ {
+		if _, ok := account.reserved[nonce]; ok {
+			continue
+		}
+		if _, ok := account.committed[nonce]; ok {
+			continue
+		}
+		gaps = append(gaps, nonce)
+	}
+	return gaps, nil
+}
+
+// Pending 已提交未确认的交易，按 nonce 升序，用于发现卡住的交易
+func (m *NonceManager) Pending(chainId *big.Int, address string) []*PendingNonce {
+	account := m.account(chainId, address)
+	account.lock.Lock()
+	defer account.lock.Unlock()
+	list := make([]*PendingNonce, 0, len(account.committed))
+	for _, pending := range account.committed {
+		p := *pending
+		list = append(list, &p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Nonce < list[j].Nonce })
+	return list
+}
+
+// Reset 丢弃本地状态，下次 Reserve 重新从链上同步
+func (m *NonceManager) Reset(chainId *big.Int, address string) {
+	key := fmt.Sprintf("%s:%s", chainId.String(), strings.ToLower(address))
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.accounts, key)
+}
@@ -0,0 +1,172 @@
+package ethWal
+
+import (
+	"errors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+	"sync"
+	"testing"
+)
+
+const nonceTestAddress = "0x3535353535353535353535353535353535353535"
+
+func nonceTestTx(nonce uint64, tip, feeCap int64) *types.Transaction {
+	to := common.HexToAddress(nonceTestAddress)
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   big.NewInt(1),
+		Nonce:     nonce,
+		GasTipCap: big.NewInt(tip),
+		GasFeeCap: big.NewInt(feeCap),
+		Gas:       21000,
+		To:        &to,
+		Value:     big.NewInt(1),
+	})
+}
+
+func TestNonceManagerReserve(t *testing.T) {
+	chainNonce := uint64(5)
+	manager := NewNonceManager(NonceSourceFunc(func(chainId *big.Int, address string) (uint64, error) {
+		return chainNonce, nil
+	}))
+	chainId := big.NewInt(1)
+
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	seen := make(map[uint64]bool)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			nonce, err := manager.Reserve(chainId, nonceTestAddress)
+			if nil != err {
+				t.Error(err)
+				return
+			}
+			lock.Lock()
+			defer lock.Unlock()
+			if seen[nonce] {
+				t.Errorf("duplicate nonce %d", nonce)
+			}
+			seen[nonce] = true
+		}()
+	}
+	wg.Wait()
+	for nonce := uint64(5); nonce < 55; nonce++ {
+		if !seen[nonce] {
+			t.Fatalf("nonce %d not allocated", nonce)
+		}
+	}
+
+	// 不同链的同一地址互不影响
+	nonce, _ := manager.Reserve(big.NewInt(56), nonceTestAddress)
+	if nonce != 5 {
+		t.Fatalf("other chain nonce %d", nonce)
+	}
+	nonce, _ = manager.Reserve(chainId, "0x3535353535353535353535353535353535353535")
+	if nonce != 55 {
+		t.Fatalf("next nonce %d", nonce)
+	}
+}
+
+func TestNonceManagerReleaseAndGaps(t *testing.T) {
+	chainNonce := uint64(0)
+	manager := NewNonceManager(NonceSourceFunc(func(chainId *big.Int, address string) (uint64, error) {
+		return chainNonce, nil
+	}))
+	chainId := big.NewInt(1)
+	for i := 0; i < 4; i++ {
+		manager.Reserve(chainId, nonceTestAddress)
+	}
+	for _, nonce := range []uint64{0, 2} {
+		if err := manager.Commit(chainId, nonceTestAddress, nonceTestTx(nonce, 1, 10)); nil != err {
+			t.Fatal(err)
+		}
+	}
+	if err := manager.Commit(chainId, nonceTestAddress, nonceTestTx(9, 1, 10)); !errors.Is(err, ErrNonceNotReserved) {
+		t.Fatalf("expected not reserved, got %v", err)
+	}
+	// 1 广播失败归还，形成空洞
+	manager.Release(chainId, nonceTestAddress, 1)
+	gaps, err := manager.Gaps(chainId, nonceTestAddress)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if len(gaps) != 1 || gaps[0] != 1 {
+		t.Fatalf("gaps %v", gaps)
+	}
+	nonce, _ := manager.Reserve(chainId, nonceTestAddress)
+	if nonce != 1 {
+		t.Fatalf("released nonce should be reused, got %d", nonce)
+	}
+	// 末尾的 nonce 归还后 next 回退
+	manager.Release(chainId, nonceTestAddress, 3)
+	nonce, _ = manager.Reserve(chainId, nonceTestAddress)
+	if nonce != 3 {
+		t.Fatalf("tail nonce should be reused, got %d", nonce)
+	}
+
+	// 链上确认 0 后同步移除
+	chainNonce = 1
+	manager.Gaps(chainId, nonceTestAddress)
+	pending := manager.Pending(chainId, nonceTestAddress)
+	if len(pending) != 1 || pending[0].Nonce != 2 {
+		t.Fatalf("pending %+v", pending)
+	}
+
+	// 外部发送了交易，本地落后时跳到链上的值
+	chainNonce = 10
+	manager.Gaps(chainId, nonceTestAddress)
+	nonce, _ = manager.Reserve(chainId, nonceTestAddress)
+	if nonce != 10 {
+		t.Fatalf("nonce should follow chain, got %d", nonce)
+	}
+}
+
+func TestNonceManagerReplace(t *testing.T) {
+	manager := NewNonceManager(NonceSourceFunc(func(chainId *big.Int, address string) (uint64, error) {
+		return 7, nil
+	}))
+	chainId := big.NewInt(1)
+	nonce, _ := manager.Reserve(chainId, nonceTestAddress)
+	manager.Commit(chainId, nonceTestAddress, nonceTestTx(nonce, 100, 1000))
+
+	tip, feeCap, err := manager.ReplacementFee(chainId, nonceTestAddress, nonce)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if tip.Int64() != 110 || feeCap.Int64() != 1100 {
+		t.Fatalf("replacement fee %s %s", tip, feeCap)
+	}
+	if err := manager.Replace(chainId, nonceTestAddress, nonceTestTx(nonce, 105, 1100)); !errors.Is(err, ErrReplacementUnderpriced) {
+		t.Fatalf("expected underpriced, got %v", err)
+	}
+	replacement := nonceTestTx(nonce, 110, 1100)
+	if err := manager.Replace(chainId, nonceTestAddress, replacement); nil != err {
+		t.Fatal(err)
+	}
+	pending := manager.Pending(chainId, nonceTestAddress)
+	if pending[0].TxHash != replacement.Hash().Hex() || pending[0].Replaced != 1 {
+		t.Fatalf("pending %+v", pending[0])
+	}
+}
+
+func TestNonceManagerCommitLegacyTx(t *testing.T) {
+	manager := NewNonceManager(NonceSourceFunc(func(chainId *big.Int, address string) (uint64, error) {
+		return 3, nil
+	}))
+	chainId := big.NewInt(56)
+	nonce, _ := manager.Reserve(chainId, nonceTestAddress)
+	to := common.HexToAddress(nonceTestAddress)
+	// 未做 EIP-155 保护的 legacy 交易 ChainId() 为 0
+	tx := types.NewTx(&types.LegacyTx{Nonce: nonce, GasPrice: big.NewInt(100), Gas: 21000, To: &to, Value: big.NewInt(1)})
+	if err := manager.Commit(chainId, nonceTestAddress, tx); nil != err {
+		t.Fatal(err)
+	}
+	if err := manager.Replace(chainId, nonceTestAddress, types.NewTx(&types.LegacyTx{Nonce: nonce, GasPrice: big.NewInt(110), Gas: 21000, To: &to, Value: big.NewInt(1)})); nil != err {
+		t.Fatal(err)
+	}
+	if pending := manager.Pending(chainId, nonceTestAddress); len(pending) != 1 || pending[0].Replaced != 1 {
+		t.Fatalf("pending %v", pending)
+	}
+}
@@ -0,0 +1,158 @@
+package ethWal
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+)
+
+// 交易类型
+const (
+	LegacyTxType     = types.LegacyTxType     // 0 legacy，ChainId 不为空时按 EIP-155 签名
+	AccessListTxType = types.AccessListTxType // 1 EIP-2930
+	DynamicFeeTxType = types.DynamicFeeTxType // 2 EIP-1559
+)
+
+// TxRequest EVM 交易参数，To 为空表示部署合约
+type TxRequest struct {
+	ChainId    *big.Int         `json:"chainId"`
+	Type       uint8            `json:"type"`
+	Nonce      uint64           `json:"nonce"`
+	To         string           `json:"to"`
+	Value      *big.Int         `json:"value"`
+	Gas        uint64           `json:"gas"`
+	GasPrice   *big.Int         `json:"gasPrice"`
+	GasTipCap  *big.Int         `json:"maxPriorityFeePerGas"`
+	GasFeeCap  *big.Int         `json:"maxFeePerGas"`
+	Data       []byte           `json:"data"`
+	AccessList types.AccessList `json:"accessList"`
+}
+
+// SignedTx 签名后的交易，RawTx 可直接用于 eth_sendRawTransaction
+type SignedTx struct {
+	RawTx  string `json:"rawTx"`
+	TxHash string `json:"txHash"`
+	From   string `json:"from"`
+	// Tx 签名后的交易对象，不参与 JSON 序列化
+	Tx *types.Transaction `json:"-"`
+}
+
+// BuildTx 构建未签名交易。ChainId 为已注册网络时：不支持 EIP-1559 的网络拒绝 type 2 交易，
+// Gas 为 0 时原生币转账与 ERC-20 transfer 使用网络的默认 gas limit
+func BuildTx(req *TxRequest) (*types.Transaction, error) {
+	gas := req.Gas
+	if req.ChainId != nil {
+		if chain, err := ChainById(req.ChainId); nil == err {
+			if req.Type == DynamicFeeTxType && !chain.EIP1559 {
+				return nil, fmt.Errorf("%s not support EIP-1559 tx", chain.Name)
+			}
+			if gas == 0 {
+				gas = chain.defaultGasLimit(req.Data)
+			}
+		}
+	}
+	if gas == 0 {
+		return nil, errors.New("tx miss gas")
+	}
+
+	var to *common.Address
+	if req.To != "" {
+		if !ValidAddress(req.To) {
+			return nil, fmt.Errorf("invalid to address: %s", req.To)
+		}
+		addr := common.HexToAddress(req.To)
+		to = &addr
+	}
+	value := req.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+
+	switch req.Type {
+	case LegacyTxType:
+		if req.GasPrice == nil {
+			return nil, errors.New("legacy tx miss gasPrice")
+		}
+		return types.NewTx(&types.LegacyTx{
+			Nonce:    req.Nonce,
+			GasPrice: req.GasPrice,
+			Gas:      gas,
+			To:       to,
+			Value:    value,
+			Data:     req.Data,
+		}), nil
+	case AccessListTxType:
+		if req.GasPrice == nil || req.ChainId == nil {
+			return nil, errors.New("access list tx miss gasPrice or chainId")
+		}
+		return types.NewTx(&types.AccessListTx{
+			ChainID:    req.ChainId,
+			Nonce:      req.Nonce,
+			GasPrice:   req.GasPrice,
+			Gas:        gas,
+			To:         to,
+			Value:      value,
+			Data:       req.Data,
+			AccessList: req.AccessList,
+		}), nil
+	case DynamicFeeTxType:
+		if req.GasTipCap == nil || req.GasFeeCap == nil || req.ChainId == nil {
+			return nil, errors.New("dynamic fee tx miss maxPriorityFeePerGas, maxFeePerGas or chainId")
+		}
+		if req.GasFeeCap.Cmp(req.GasTipCap) < 0 {
+			return nil, errors.New("maxFeePerGas less than maxPriorityFeePerGas")
+		}
+		return types.NewTx(&types.DynamicFeeTx{
+			ChainID:    req.ChainId,
+			Nonce:      req.Nonce,
+			GasTipCap:  req.GasTipCap,
+			GasFeeCap:  req.GasFeeCap,
+			Gas:        gas,
+			To:         to,
+			Value:      value,
+			Data:       req.Data,
+			AccessList: req.AccessList,
+		}), nil
+	}
+	return nil, fmt.Errorf("unsupported tx type: %d", req.Type)
+}
+
+// SignTx 构建并签名交易
+func SignTx(req *TxRequest, privateKey *ecdsa.PrivateKey) (*SignedTx, error) {
+	tx, err := BuildTx(req)
+	if nil != err {
+		return nil, err
+	}
+	return SignTransaction(tx, req.ChainId, privateKey)
+}
+
+// SignTransaction 签名交易，chainId 为空时 legacy 交易按 Homestead 规则签名（无重放保护）
+func SignTransaction(tx *types.Transaction, chainId *big.Int, privateKey *ecdsa.PrivateKey) (*SignedTx, error) {
+	var signer types.Signer
+	if chainId == nil || chainId.Sign() == 0 {
+		if tx.Type() != LegacyTxType {
+			return nil, errors.New("typed tx require chainId")
+		}
+		signer = types.HomesteadSigner{}
+	} else {
+		signer = types.LatestSignerForChainID(chainId)
+	}
+	signedTx, err := types.SignTx(tx, signer, privateKey)
+	if nil != err {
+		return nil, fmt.Errorf("sign error: %v", err)
+	}
+	raw, err := signedTx.MarshalBinary()
+	if nil != err {
+		return nil, err
+	}
+	return &SignedTx{
+		RawTx:  hexutil.Encode(raw),
+		TxHash: signedTx.Hash().Hex(),
+		From:   PrivateKeyToAddressETH(privateKey),
+		Tx:     signedTx,
+	}, nil
+}
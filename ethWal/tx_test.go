@@ -0,0 +1,72 @@
+package ethWal
+
+import (
+	"github.com/PandaManPMC/txBuilder/hdWallet"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+	"testing"
+)
+
+// EIP-155 规范中的示例
+func TestSignTxEIP155(t *testing.T) {
+	privateKey, err := hdWallet.GetInstanceByHDWalletUtil().LoadWalletByPrivateKey("4646464646464646464646464646464646464646464646464646464646464646")
+	if nil != err {
+		t.Fatal(err)
+	}
+	value, _ := new(big.Int).SetString("1000000000000000000", 10)
+	signed, err := SignTx(&TxRequest{
+		ChainId:  big.NewInt(1),
+		Type:     LegacyTxType,
+		Nonce:    9,
+		To:       "0x3535353535353535353535353535353535353535",
+		Value:    value,
+		Gas:      21000,
+		GasPrice: big.NewInt(20000000000),
+	}, privateKey)
+	if nil != err {
+		t.Fatal(err)
+	}
+	expect := "0xf86c098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a76400008025a028ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276a067cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83"
+	if signed.RawTx != expect {
+		t.Fatalf("raw tx mismatch %s", signed.RawTx)
+	}
+	t.Log(signed.TxHash, signed.From)
+}
+
+func TestSignTxTyped(t *testing.T) {
+	privateKey, err := hdWallet.GetInstanceByHDWalletUtil().LoadWalletByPrivateKey("1ea107cf1e8cbca5a1e9ee2661505b1836db495c574eace64ecdbc20b29b83fd")
+	if nil != err {
+		t.Fatal(err)
+	}
+	chainId, err := ChainIdByNetWork("BSC")
+	if nil != err {
+		t.Fatal(err)
+	}
+	accessList := types.AccessList{{Address: common.HexToAddress("0x55d398326f99059fF775485246999027B3197955"), StorageKeys: []common.Hash{{}}}}
+	for _, req := range []*TxRequest{
+		{ChainId: chainId, Type: AccessListTxType, Nonce: 1, To: "0x6ef25ea3f4cceae27d57cbe9a4cfdd2ded2b2740", Value: big.NewInt(1), Gas: 30000, GasPrice: big.NewInt(3000000000), AccessList: accessList},
+		{ChainId: chainId, Type: DynamicFeeTxType, Nonce: 2, To: "0x6ef25ea3f4cceae27d57cbe9a4cfdd2ded2b2740", Value: big.NewInt(1), Gas: 21000, GasTipCap: big.NewInt(1000000000), GasFeeCap: big.NewInt(5000000000)},
+	} {
+		signed, err := SignTx(req, privateKey)
+		if nil != err {
+			t.Fatal(err)
+		}
+		tx := new(types.Transaction)
+		if err = tx.UnmarshalBinary(common.FromHex(signed.RawTx)); nil != err {
+			t.Fatal(err)
+		}
+		sender, err := types.Sender(types.LatestSignerForChainID(chainId), tx)
+		if nil != err {
+			t.Fatal(err)
+		}
+		if tx.Type() != req.Type || tx.Hash().Hex() != signed.TxHash || sender.Hex() != common.HexToAddress(PrivateKeyToAddressETH(privateKey)).Hex() {
+			t.Fatalf("unexpected tx %s", signed.RawTx)
+		}
+		t.Log(signed.RawTx)
+	}
+
+	if _, err = SignTx(&TxRequest{ChainId: chainId, Type: DynamicFeeTxType, Gas: 21000, GasTipCap: big.NewInt(2), GasFeeCap: big.NewInt(1)}, privateKey); nil == err {
+		t.Fatal("expected fee cap error")
+	}
+}
@@ -10,17 +10,17 @@ import (
 	"strings"
 )
 
+// PubKeyToAddressETH 公钥转 EIP-55 校验和地址，X/Y 各补齐 32 字节后再哈希
 func PubKeyToAddressETH(publicKey ecdsa.PublicKey) string {
-
-	publicKeyBytes := append(publicKey.X.Bytes(), publicKey.Y.Bytes()...)
+	publicKeyBytes := make([]byte, 64)
+	publicKey.X.FillBytes(publicKeyBytes[:32])
+	publicKey.Y.FillBytes(publicKeyBytes[32:])
 
 	hash := sha3.NewLegacyKeccak256()
 	hash.Write(publicKeyBytes)
 	hashed := hash.Sum(nil)
 
-	address := hashed[len(hashed)-20:]
-
-	return fmt.Sprintf("0x%s", hex.EncodeToString(address))
+	return checksumAddress(hashed[len(hashed)-20:])
 }
 
 func PrivateKeyToAddressETH(privateKey *ecdsa.PrivateKey) string {
@@ -42,9 +42,62 @@ func ImportWallet(mnemonic string, index int) (privateKey *ecdsa.PrivateKey, add
 	return
 }
 
-// ValidAddress 校验 ETH 地址格式（0x 开头，40 个十六进制字符）
+var addressRegexp = regexp.MustCompile("^0[xX][a-fA-F0-9]{40}$")
+
+// ValidAddress 校验 ETH 地址格式（0x 开头，40 个十六进制字符），
+// 全小写或全大写视为未带校验和，大小写混合时必须符合 EIP-55 校验和
 func ValidAddress(address string) bool {
-	address = strings.ToLower(address)
-	match, _ := regexp.MatchString("^0x[a-fA-F0-9]{40}$", address)
-	return match
+	if !addressRegexp.MatchString(address) {
+		return false
+	}
+	body := address[2:]
+	if body == strings.ToLower(body) || body == strings.ToUpper(body) {
+		return true
+	}
+	return address[:2] == "0x" && ChecksumAddress(address) == address
+}
+
+// ChecksumAddress 转为 EIP-55 校验和格式，address 须为 0x 开头的 40 位十六进制，否则返回空字符串
+func ChecksumAddress(address string) string {
+	if !addressRegexp.MatchString(address) {
+		return ""
+	}
+	b, _ := hex.DecodeString(address[2:])
+	return checksumAddress(b)
+}
+
+// checksumAddress EIP-55：对小写十六进制地址做 keccak256，对应半字节 >= 8 的字母转大写
+func checksumAddress(address []byte) string {
+	lower := []byte(hex.EncodeToString(address))
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(lower)
+	hashed := hash.Sum(nil)
+	for i, c := range lower {
+		if c < 'a' {
+			continue
+		}
+		nibble := hashed[i/2]
+		if i%2 == 0 {
+			nibble >>= 4
+		}
+		if nibble&0x0f >= 8 {
+			lower[i] = c - 32
+		}
+	}
+	return "0x" + string(lower)
+}
+
+// ImportKeystore 导入 geth/MetaMask 导出的 keystore V3 JSON
+func ImportKeystore(keystoreJson []byte, password string) (privateKey *ecdsa.PrivateKey, address string, err error) {
+	privateKey, err = hdWallet.GetInstanceByHDWalletUtil().DecryptKeystore(keystoreJson, password)
+	if nil != err {
+		return nil, "", err
+	}
+	address = PrivateKeyToAddressETH(privateKey)
+	return
+}
+
+// ExportKeystore 导出 keystore V3 JSON，kdf 见 hdWallet.KdfScrypt 等
+func ExportKeystore(privateKey *ecdsa.PrivateKey, password string, kdf string) ([]byte, error) {
+	return hdWallet.GetInstanceByHDWalletUtil().EncryptKeystore(privateKey, password, kdf)
 }
@@ -47,3 +47,63 @@ func TestIValidAddress(t *testing.T) {
 	t.Log(ValidAddress("0xa07880f94796250e9b37F4aFbcbAeb1e55A385c"))
 	t.Log(ValidAddress(""))
 }
+
+// EIP-55 规范中的示例
+func TestChecksumAddress(t *testing.T) {
+	for _, address := range []string{
+		"0x52908400098527886E0F7030069857D2E4169EE7",
+		"0x8617E340B3D01FA5F11F306F4090FD50E238070D",
+		"0xde709f2102306220921060314715629080e2fb77",
+		"0x27b1fdb04752bbc536007a920d24acb045561c26",
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+	} {
+		if !ValidAddress(address) {
+			t.Fatalf("%s should be valid", address)
+		}
+	}
+	if ChecksumAddress("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed") != "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed" {
+		t.Fatal("checksum mismatch")
+	}
+	// 大小写混合但校验和错误
+	if ValidAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD") {
+		t.Fatal("bad checksum should be invalid")
+	}
+	if ValidAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAe") || ValidAddress("") {
+		t.Fatal("bad length should be invalid")
+	}
+}
+
+// 私钥 0x7a 的公钥 Y 坐标只有 31 字节，未补齐会得到错误地址
+func TestPubKeyToAddressPadding(t *testing.T) {
+	privateKey, err := hdWallet.GetInstanceByHDWalletUtil().LoadWalletByPrivateKey("000000000000000000000000000000000000000000000000000000000000007a")
+	if nil != err {
+		t.Fatal(err)
+	}
+	if len(privateKey.PublicKey.Y.Bytes()) != 31 {
+		t.Fatal("vector should have short Y")
+	}
+	if address := PrivateKeyToAddressETH(privateKey); address != "0x872917cEC8992487651Ee633DBA73bd3A9dcA309" {
+		t.Fatalf("address mismatch %s", address)
+	}
+}
+
+func TestKeystore(t *testing.T) {
+	privateKey, address, err := ImportWallet("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", 0)
+	if nil != err {
+		t.Fatal(err)
+	}
+	keystoreJson, err := ExportKeystore(privateKey, "pass", hdWallet.KdfPbkdf2)
+	if nil != err {
+		t.Fatal(err)
+	}
+	_, imported, err := ImportKeystore(keystoreJson, "pass")
+	if nil != err {
+		t.Fatal(err)
+	}
+	if imported != address {
+		t.Fatalf("address mismatch %s %s", imported, address)
+	}
+}
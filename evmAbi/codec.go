@@ -0,0 +1,573 @@
+package evmAbi
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/crypto/sha3"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// 以太坊合约 ABI 编解码，EVM 链与 TRON（TRC-20/721）共用。
+// 支持 uintN/intN/address/bool/bytesN/bytes/string、定长与变长数组以及 tuple，tuple 写作 "(address,uint256)"。
+// 编码时 uint/int 接受 *big.Int 与各整型，address 接受 common.Address 或 hex 字符串（含 TRON 41 前缀的 21 字节 hex），
+// 数组与 tuple 接受任意 slice（通常为 []interface{}）。解码后 uint/int 为 *big.Int，address 为 common.Address，
+// bytesN/bytes 为 []byte，数组与 tuple 为 []interface{}。
+
+type kind int
+
+const (
+	kindUint kind = iota
+	kindInt
+	kindAddress
+	kindBool
+	kindFixedBytes
+	kindBytes
+	kindString
+	kindSlice
+	kindArray
+	kindTuple
+)
+
+type abiType struct {
+	kind       kind
+	size       int
+	elem       *abiType
+	components []*abiType
+}
+
+// Selector 函数选择器，signature 形如 "transfer(address,uint256)"
+func Selector(signature string) []byte {
+	return Keccak256([]byte(strings.ReplaceAll(signature, " ", "")))[:4]
+}
+
+// EventTopic 事件签名哈希，即 topics[0]
+func EventTopic(signature string) common.Hash {
+	return common.BytesToHash(Keccak256([]byte(strings.ReplaceAll(signature, " ", ""))))
+}
+
+func Keccak256(data []byte) []byte {
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(data)
+	return hash.Sum(nil)
+}
+
+// EncodeArgs 按类型列表编码参数
+func EncodeArgs(typeList []string, values ...interface{}) ([]byte, error) {
+	types, err := parseTypeList(typeList)
+	if nil != err {
+		return nil, err
+	}
+	return encodeSequence(types, values)
+}
+
+// DecodeArgs 按类型列表解码参数
+func DecodeArgs(typeList []string, data []byte) ([]interface{}, error) {
+	types, err := parseTypeList(typeList)
+	if nil != err {
+		return nil, err
+	}
+	return decodeSequence(types, data)
+}
+
+// EncodeCall 生成合约调用 calldata：selector + 参数编码
+func EncodeCall(signature string, values ...interface{}) ([]byte, error) {
+	typeList, err := SignatureTypes(signature)
+	if nil != err {
+		return nil, err
+	}
+	args, err := EncodeArgs(typeList, values...)
+	if nil != err {
+		return nil, err
+	}
+	return append(Selector(signature), args...), nil
+}
+
+// EncodeCallHex 同 EncodeCall，返回不带 0x 的 hex，TRON triggersmartcontract 的 data 即此格式
+func EncodeCallHex(signature string, values ...interface{}) (string, error) {
+	data, err := EncodeCall(signature, values...)
+	if nil != err {
+		return "", err
+	}
+	return hex.EncodeToString(data), nil
+}
+
+// DecodeCall 校验 selector 并解码 calldata 参数
+func DecodeCall(signature string, data []byte) ([]interface{}, error) {
+	if len(data) < 4 {
+		return nil, errors.New("calldata too short")
+	}
+	if !reflect.DeepEqual(data[:4], Selector(signature)) {
+		return nil, fmt.Errorf("selector mismatch %x", data[:4])
+	}
+	typeList, err := SignatureTypes(signature)
+	if nil != err {
+		return nil, err
+	}
+	return DecodeArgs(typeList, data[4:])
+}
+
+// SignatureTypes 从函数签名中取出参数类型列表
+func SignatureTypes(signature string) ([]string, error) {
+	signature = strings.ReplaceAll(signature, " ", "")
+	start := strings.Index(signature, "(")
+	if start <= 0 || !strings.HasSuffix(signature, ")") {
+		return nil, fmt.Errorf("invalid signature: %s", signature)
+	}
+	return splitTopLevel(signature[start+1 : len(signature)-1])
+}
+
+func splitTopLevel(s string) ([]string, error) {
+	if s == "" {
+		return []string{}, nil
+	}
+	parts := make([]string, 0)
+	depth, last := 0, 0
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced parentheses: %s", s)
+			}
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[last:i])
+				last = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced parentheses: %s", s)
+	}
+	return append(parts, s[last:]), nil
+}
+
+func parseTypeList(typeList []string) ([]*abiType, error) {
+	types := make([]*abiType, len(typeList))
+	for i, s := range typeList {
+		t, err := parseType(s)
+		if nil != err {
+			return nil, err
+		}
+		types[i] = t
+	}
+	return types, nil
+}
+
+func parseType(s string) (*abiType, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasSuffix(s, "]") {
+		idx := strings.LastIndex(s, "[")
+		if idx <= 0 {
+			return nil, fmt.Errorf("invalid type: %s", s)
+		}
+		elem, err := parseType(s[:idx])
+		if nil != err {
+			return nil, err
+		}
+		length := s[idx+1 : len(s)-1]
+		if length == "" {
+			return &abiType{kind: kindSlice, elem: elem}, nil
+		}
+		n, err := strconv.Atoi(length)
+		if nil != err || n <= 0 {
+			return nil, fmt.Errorf("invalid array length: %s", s)
+		}
+		return &abiType{kind: kindArray, size: n, elem: elem}, nil
+	}
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		parts, err := splitTopLevel(s[1 : len(s)-1])
+		if nil != err {
+			return nil, err
+		}
+		components, err := parseTypeList(parts)
+		if nil != err {
+			return nil, err
+		}
+		return &abiType{kind: kindTuple, components: components}, nil
+	}
+	switch s {
+	case "address":
+		return &abiType{kind: kindAddress}, nil
+	case "bool":
+		return &abiType{kind: kindBool}, nil
+	case "bytes":
+		return &abiType{kind: kindBytes}, nil
+	case "string":
+		return &abiType{kind: kindString}, nil
+	case "uint":
+		return &abiType{kind: kindUint, size: 256}, nil
+	case "int":
+		return &abiType{kind: kindInt, size: 256}, nil
+	}
+	parseSize := func(prefix string, max, step int) (int, bool) {
+		n, err := strconv.Atoi(strings.TrimPrefix(s, prefix))
+		return n, nil == err && n > 0 && n <= max && n%step == 0
+	}
+	switch {
+	case strings.HasPrefix(s, "uint"):
+		if n, ok := parseSize("uint", 256, 8); ok {
+			return &abiType{kind: kindUint, size: n}, nil
+		}
+	case strings.HasPrefix(s, "int"):
+		if n, ok := parseSize("int", 256, 8); ok {
+			return &abiType{kind: kindInt, size: n}, nil
+		}
+	case strings.HasPrefix(s, "bytes"):
+		if n, ok := parseSize("bytes", 32, 1); ok {
+			return &abiType{kind: kindFixedBytes, size: n}, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported type: %s", s)
+}
+
+func (t *abiType) isDynamic() bool {
+	switch t.kind {
+	case kindBytes, kindString, kindSlice:
+		return true
+	case kindArray:
+		return t.elem.isDynamic()
+	case kindTuple:
+		for _, c := range t.components {
+			if c.isDynamic() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// headSize 静态类型编码后的长度，动态类型在头部只占 32 字节的偏移量
+func (t *abiType) headSize() int {
+	if t.isDynamic() {
+		return 32
+	}
+	switch t.kind {
+	case kindArray:
+		return t.size * t.elem.headSize()
+	case kindTuple:
+		size := 0
+		for _, c := range t.components {
+			size += c.headSize()
+		}
+		return size
+	}
+	return 32
+}
+
+func encodeSequence(types []*abiType, values []interface{}) ([]byte, error) {
+	if len(types) != len(values) {
+		return nil, fmt.Errorf("argument count mismatch, need %d got %d", len(types), len(values))
+	}
+	headLen := 0
+	for _, t := range types {
+		headLen += t.headSize()
+	}
+	head := make([]byte, 0, headLen)
+	tail := make([]byte, 0)
+	for i, t := range types {
+		encoded, err := encodeValue(t, values[i])
+		if nil != err {
+			return nil, err
+		}
+		if t.isDynamic() {
+			head = append(head, padLeft(big.NewInt(int64(headLen+len(tail))).Bytes())...)
+			tail = append(tail, encoded...)
+		} else {
+			head = append(head, encoded...)
+		}
+	}
+	return append(head, tail...), nil
+}
+
+func encodeValue(t *abiType, v interface{}) ([]byte, error) {
+	switch t.kind {
+	case kindUint, kindInt:
+		n, err := toBigInt(v)
+		if nil != err {
+			return nil, err
+		}
+		return encodeInt(t, n)
+	case kindAddress:
+		addr, err := toAddress(v)
+		if nil != err {
+			return nil, err
+		}
+		return padLeft(addr.Bytes()), nil
+	case kindBool:
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("bool type expect bool, got %T", v)
+		}
+		if b {
+			return padLeft([]byte{1}), nil
+		}
+		return make([]byte, 32), nil
+	case kindFixedBytes:
+		b, err := toBytes(v)
+		if nil != err {
+			return nil, err
+		}
+		if len(b) > t.size {
+			return nil, fmt.Errorf("bytes%d overflow, length %d", t.size, len(b))
+		}
+		return padRight(b), nil
+	case kindBytes, kindString:
+		var b []byte
+		if s, ok := v.(string); ok && t.kind == kindString {
+			b = []byte(s)
+		} else {
+			var err error
+			if b, err = toBytes(v); nil != err {
+				return nil, err
+			}
+		}
+		out := padLeft(big.NewInt(int64(len(b))).Bytes())
+		if len(b) > 0 {
+			out = append(out, padRight(b)...)
+		}
+		return out, nil
+	case kindSlice, kindArray:
+		elems, err := toSlice(v)
+		if nil != err {
+			return nil, err
+		}
+		if t.kind == kindArray && len(elems) != t.size {
+			return nil, fmt.Errorf("array length mismatch, need %d got %d", t.size, len(elems))
+		}
+		types := make([]*abiType, len(elems))
+		for i := range elems {
+			types[i] = t.elem
+		}
+		encoded, err := encodeSequence(types, elems)
+		if nil != err {
+			return nil, err
+		}
+		if t.kind == kindSlice {
+			return append(padLeft(big.NewInt(int64(len(elems))).Bytes()), encoded...), nil
+		}
+		return encoded, nil
+	case kindTuple:
+		elems, err := toSlice(v)
+		if nil != err {
+			return nil, err
+		}
+		return encodeSequence(t.components, elems)
+	}
+	return nil, errors.New("unsupported type")
+}
+
+func encodeInt(t *abiType, n *big.Int) ([]byte, error) {
+	if t.kind == kindUint {
+		if n.Sign() < 0 || n.BitLen() > t.size {
+			return nil, fmt.Errorf("uint%d overflow: %s", t.size, n.String())
+		}
+		return padLeft(n.Bytes()), nil
+	}
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(t.size-1))
+	if n.Cmp(limit) >= 0 || n.Cmp(new(big.Int).Neg(limit)) < 0 {
+		return nil, fmt.Errorf("int%d overflow: %s", t.size, n.String())
+	}
+	if n.Sign() >= 0 {
+		return padLeft(n.Bytes()), nil
+	}
+	// 负数按 256 位补码编码
+	twos := new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 256), n)
+	return padLeft(twos.Bytes()), nil
+}
+
+func decodeSequence(types []*abiType, data []byte) ([]interface{}, error) {
+	values := make([]interface{}, len(types))
+	pos := 0
+	for i, t := range types {
+		size := t.headSize()
+		if pos+size > len(data) {
+			return nil, errors.New("abi data too short")
+		}
+		if t.isDynamic() {
+			offset, err := readLength(data[pos : pos+32])
+			if nil != err {
+				return nil, err
+			}
+			if offset > len(data) {
+				return nil, errors.New("abi offset out of range")
+			}
+			if values[i], err = decodeValue(t, data[offset:]); nil != err {
+				return nil, err
+			}
+		} else {
+			var err error
+			if values[i], err = decodeValue(t, data[pos:pos+size]); nil != err {
+				return nil, err
+			}
+		}
+		pos += size
+	}
+	return values, nil
+}
+
+func decodeValue(t *abiType, data []byte) (interface{}, error) {
+	if len(data) < 32 && t.kind != kindTuple && t.kind != kindArray {
+		return nil, errors.New("abi data too short")
+	}
+	switch t.kind {
+	case kindUint:
+		n := new(big.Int).SetBytes(data[:32])
+		if n.BitLen() > t.size {
+			return nil, fmt.Errorf("uint%d overflow", t.size)
+		}
+		return n, nil
+	case kindInt:
+		n := new(big.Int).SetBytes(data[:32])
+		if data[0]&0x80 != 0 {
+			n.Sub(n, new(big.Int).Lsh(big.NewInt(1), 256))
+		}
+		return n, nil
+	case kindAddress:
+		return common.BytesToAddress(data[12:32]), nil
+	case kindBool:
+		return data[31] == 1, nil
+	case kindFixedBytes:
+		return common.CopyBytes(data[:t.size]), nil
+	case kindBytes, kindString:
+		length, err := readLength(data[:32])
+		if nil != err {
+			return nil, err
+		}
+		if 32+length > len(data) {
+			return nil, errors.New("abi bytes out of range")
+		}
+		b := common.CopyBytes(data[32 : 32+length])
+		if t.kind == kindString {
+			return string(b), nil
+		}
+		return b, nil
+	case kindSlice, kindArray:
+		length := t.size
+		if t.kind == kindSlice {
+			var err error
+			if length, err = readLength(data[:32]); nil != err {
+				return nil, err
+			}
+			data = data[32:]
+		}
+		if length > len(data) {
+			return nil, errors.New("abi array length out of range")
+		}
+		types := make([]*abiType, length)
+		for i := range types {
+			types[i] = t.elem
+		}
+		return decodeSequence(types, data)
+	case kindTuple:
+		return decodeSequence(t.components, data)
+	}
+	return nil, errors.New("unsupported type")
+}
+
+func readLength(word []byte) (int, error) {
+	n := new(big.Int).SetBytes(word)
+	if !n.IsInt64() || n.Int64() > int64(^uint32(0)) {
+		return 0, errors.New("abi length too large")
+	}
+	return int(n.Int64()), nil
+}
+
+func padLeft(b []byte) []byte {
+	return common.LeftPadBytes(b, 32)
+}
+
+func padRight(b []byte) []byte {
+	size := (len(b) + 31) / 32 * 32
+	if size == 0 {
+		size = 32
+	}
+	return common.RightPadBytes(b, size)
+}
+
+func toBigInt(v interface{}) (*big.Int, error) {
+	switch n := v.(type) {
+	case *big.Int:
+		if n == nil {
+			return nil, errors.New("nil big.Int")
+		}
+		return n, nil
+	case big.Int:
+		return &n, nil
+	case string:
+		i, ok := new(big.Int).SetString(n, 0)
+		if !ok {
+			return nil, fmt.Errorf("invalid integer: %s", n)
+		}
+		return i, nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Int).SetUint64(rv.Uint()), nil
+	}
+	return nil, fmt.Errorf("integer type expect *big.Int or int, got %T", v)
+}
+
+func toAddress(v interface{}) (common.Address, error) {
+	switch a := v.(type) {
+	case common.Address:
+		return a, nil
+	case *common.Address:
+		return *a, nil
+	case string:
+		b, err := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(a, "0x"), "0X"))
+		if nil != err {
+			return common.Address{}, fmt.Errorf("invalid address: %s", a)
+		}
+		// TRON hex 地址 41 + 20 字节
+		if len(b) == 21 && b[0] == 0x41 {
+			b = b[1:]
+		}
+		if len(b) != common.AddressLength {
+			return common.Address{}, fmt.Errorf("invalid address: %s", a)
+		}
+		return common.BytesToAddress(b), nil
+	}
+	return common.Address{}, fmt.Errorf("address type expect common.Address or string, got %T", v)
+}
+
+func toBytes(v interface{}) ([]byte, error) {
+	if b, ok := v.([]byte); ok {
+		return b, nil
+	}
+	if h, ok := v.(common.Hash); ok {
+		return h.Bytes(), nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Array && rv.Type().Elem().Kind() == reflect.Uint8 {
+		b := make([]byte, rv.Len())
+		reflect.Copy(reflect.ValueOf(b), rv)
+		return b, nil
+	}
+	return nil, fmt.Errorf("bytes type expect []byte, got %T", v)
+}
+
+func toSlice(v interface{}) ([]interface{}, error) {
+	if s, ok := v.([]interface{}); ok {
+		return s, nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("array type expect slice, got %T", v)
+	}
+	s := make([]interface{}, rv.Len())
+	for i := range s {
+		s[i] = rv.Index(i).Interface()
+	}
+	return s, nil
+}
@@ -0,0 +1,127 @@
+package evmAbi
+
+import (
+	"bytes"
+	"encoding/hex"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// Solidity ABI 规范中的示例
+func TestEncodeCallSpec(t *testing.T) {
+	data, err := EncodeCall("f(uint256,uint32[],bytes10,bytes)", big.NewInt(0x123), []interface{}{0x456, 0x789}, []byte("1234567890"), []byte("Hello, world!"))
+	if nil != err {
+		t.Fatal(err)
+	}
+	expect := "8be65246" +
+		"0000000000000000000000000000000000000000000000000000000000000123" +
+		"0000000000000000000000000000000000000000000000000000000000000080" +
+		"3132333435363738393000000000000000000000000000000000000000000000" +
+		"00000000000000000000000000000000000000000000000000000000000000e0" +
+		"0000000000000000000000000000000000000000000000000000000000000002" +
+		"0000000000000000000000000000000000000000000000000000000000000456" +
+		"0000000000000000000000000000000000000000000000000000000000000789" +
+		"000000000000000000000000000000000000000000000000000000000000000d" +
+		"48656c6c6f2c20776f726c642100000000000000000000000000000000000000"
+	if hex.EncodeToString(data) != expect {
+		t.Fatalf("encode mismatch %x", data)
+	}
+
+	data, err = EncodeCall("g(uint256[][],string[])", [][]int{{1, 2}, {3}}, []string{"one", "two", "three"})
+	if nil != err {
+		t.Fatal(err)
+	}
+	expect = "2289b18c" +
+		"0000000000000000000000000000000000000000000000000000000000000040" +
+		"0000000000000000000000000000000000000000000000000000000000000140" +
+		"0000000000000000000000000000000000000000000000000000000000000002" +
+		"0000000000000000000000000000000000000000000000000000000000000040" +
+		"00000000000000000000000000000000000000000000000000000000000000a0" +
+		"0000000000000000000000000000000000000000000000000000000000000002" +
+		"0000000000000000000000000000000000000000000000000000000000000001" +
+		"0000000000000000000000000000000000000000000000000000000000000002" +
+		"0000000000000000000000000000000000000000000000000000000000000001" +
+		"0000000000000000000000000000000000000000000000000000000000000003" +
+		"0000000000000000000000000000000000000000000000000000000000000003" +
+		"0000000000000000000000000000000000000000000000000000000000000060" +
+		"00000000000000000000000000000000000000000000000000000000000000a0" +
+		"00000000000000000000000000000000000000000000000000000000000000e0" +
+		"0000000000000000000000000000000000000000000000000000000000000003" +
+		"6f6e650000000000000000000000000000000000000000000000000000000000" +
+		"0000000000000000000000000000000000000000000000000000000000000003" +
+		"74776f0000000000000000000000000000000000000000000000000000000000" +
+		"0000000000000000000000000000000000000000000000000000000000000005" +
+		"7468726565000000000000000000000000000000000000000000000000000000"
+	if hex.EncodeToString(data) != expect {
+		t.Fatalf("encode mismatch %x", data)
+	}
+
+	args, err := DecodeCall("g(uint256[][],string[])", data)
+	if nil != err {
+		t.Fatal(err)
+	}
+	names := args[1].([]interface{})
+	if len(names) != 3 || names[2].(string) != "three" || args[0].([]interface{})[1].([]interface{})[0].(*big.Int).Int64() != 3 {
+		t.Fatalf("decode mismatch %v", args)
+	}
+}
+
+// tuple 与负数编码与 go-ethereum 的实现对照
+func TestEncodeTuple(t *testing.T) {
+	tupleType, err := abi.NewType("tuple[]", "", []abi.ArgumentMarshaling{
+		{Name: "to", Type: "address"},
+		{Name: "amount", Type: "int128"},
+		{Name: "memo", Type: "bytes"},
+	})
+	if nil != err {
+		t.Fatal(err)
+	}
+	boolType, _ := abi.NewType("bool", "", nil)
+	arguments := abi.Arguments{{Type: tupleType}, {Type: boolType}}
+	type item struct {
+		To     common.Address
+		Amount *big.Int
+		Memo   []byte
+	}
+	to := common.HexToAddress("0x6ef25ea3f4cceae27d57cbe9a4cfdd2ded2b2740")
+	expect, err := arguments.Pack([]item{{To: to, Amount: big.NewInt(-5), Memo: []byte("abc")}, {To: to, Amount: big.NewInt(7), Memo: nil}}, true)
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	data, err := EncodeArgs([]string{"(address,int128,bytes)[]", "bool"},
+		[]interface{}{
+			[]interface{}{to, big.NewInt(-5), []byte("abc")},
+			[]interface{}{"0x6ef25ea3f4cceae27d57cbe9a4cfdd2ded2b2740", 7, []byte{}},
+		}, true)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(expect, data) {
+		t.Fatalf("encode mismatch\n%x\n%x", expect, data)
+	}
+
+	values, err := DecodeArgs([]string{"(address,int128,bytes)[]", "bool"}, data)
+	if nil != err {
+		t.Fatal(err)
+	}
+	first := values[0].([]interface{})[0].([]interface{})
+	if first[0].(common.Address) != to || first[1].(*big.Int).Int64() != -5 || string(first[2].([]byte)) != "abc" || values[1].(bool) != true {
+		t.Fatalf("decode mismatch %v", values)
+	}
+}
+
+func TestEncodeOverflow(t *testing.T) {
+	if _, err := EncodeArgs([]string{"uint8"}, 256); nil == err {
+		t.Fatal("expected uint8 overflow")
+	}
+	if _, err := EncodeArgs([]string{"int8"}, -129); nil == err {
+		t.Fatal("expected int8 overflow")
+	}
+	if _, err := EncodeArgs([]string{"address"}, "0x1234"); nil == err || !strings.Contains(err.Error(), "invalid address") {
+		t.Fatal("expected address error")
+	}
+}
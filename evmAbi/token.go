@@ -0,0 +1,177 @@
+package evmAbi
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+)
+
+// 常用代币方法签名
+const (
+	SigTransfer                 = "transfer(address,uint256)"
+	SigApprove                  = "approve(address,uint256)"
+	SigTransferFrom             = "transferFrom(address,address,uint256)"
+	SigSafeTransferFrom         = "safeTransferFrom(address,address,uint256)"
+	SigSafeTransferFromWithData = "safeTransferFrom(address,address,uint256,bytes)"
+	SigERC1155SafeTransferFrom  = "safeTransferFrom(address,address,uint256,uint256,bytes)"
+	SigERC1155BatchTransferFrom = "safeBatchTransferFrom(address,address,uint256[],uint256[],bytes)"
+
+	EventTransfer       = "Transfer(address,address,uint256)"
+	EventTransferSingle = "TransferSingle(address,address,address,uint256,uint256)"
+	EventTransferBatch  = "TransferBatch(address,address,address,uint256[],uint256[])"
+)
+
+// ERC20Transfer transfer(to, amount)，TRC-20 同样适用
+func ERC20Transfer(to interface{}, amount *big.Int) ([]byte, error) {
+	return EncodeCall(SigTransfer, to, amount)
+}
+
+// ERC20Approve approve(spender, amount)
+func ERC20Approve(spender interface{}, amount *big.Int) ([]byte, error) {
+	return EncodeCall(SigApprove, spender, amount)
+}
+
+// ERC20TransferFrom transferFrom(from, to, amount)，ERC-721 的 transferFrom 编码相同，第三个参数为 tokenId
+func ERC20TransferFrom(from, to interface{}, amount *big.Int) ([]byte, error) {
+	return EncodeCall(SigTransferFrom, from, to, amount)
+}
+
+// ERC721SafeTransferFrom safeTransferFrom(from, to, tokenId)，data 不为空时使用带 bytes 参数的重载
+func ERC721SafeTransferFrom(from, to interface{}, tokenId *big.Int, data []byte) ([]byte, error) {
+	if len(data) > 0 {
+		return EncodeCall(SigSafeTransferFromWithData, from, to, tokenId, data)
+	}
+	return EncodeCall(SigSafeTransferFrom, from, to, tokenId)
+}
+
+// ERC1155SafeTransferFrom safeTransferFrom(from, to, id, amount, data)
+func ERC1155SafeTransferFrom(from, to interface{}, id, amount *big.Int, data []byte) ([]byte, error) {
+	return EncodeCall(SigERC1155SafeTransferFrom, from, to, id, amount, data)
+}
+
+// ERC1155SafeBatchTransferFrom safeBatchTransferFrom(from, to, ids, amounts, data)
+func ERC1155SafeBatchTransferFrom(from, to interface{}, ids, amounts []*big.Int, data []byte) ([]byte, error) {
+	if len(ids) != len(amounts) {
+		return nil, errors.New("ids and amounts length mismatch")
+	}
+	return EncodeCall(SigERC1155BatchTransferFrom, from, to, ids, amounts, data)
+}
+
+// TokenCall 解码后的代币调用，未涉及的字段为空
+type TokenCall struct {
+	Method  string         `json:"method"`
+	From    common.Address `json:"from"`
+	To      common.Address `json:"to"`
+	Spender common.Address `json:"spender"`
+	Amount  *big.Int       `json:"amount"`
+	TokenId *big.Int       `json:"tokenId"`
+	Ids     []*big.Int     `json:"ids"`
+	Amounts []*big.Int     `json:"amounts"`
+	Data    []byte         `json:"data"`
+}
+
+// DecodeTokenCall 按 selector 识别 transfer/approve/transferFrom/safeTransferFrom/safeBatchTransferFrom。
+// transferFrom 无法区分 ERC-20 与 ERC-721，第三个参数同时写入 Amount 与 TokenId
+func DecodeTokenCall(data []byte) (*TokenCall, error) {
+	if len(data) < 4 {
+		return nil, errors.New("calldata too short")
+	}
+	for _, signature := range []string{SigTransfer, SigApprove, SigTransferFrom, SigSafeTransferFrom, SigSafeTransferFromWithData, SigERC1155SafeTransferFrom, SigERC1155BatchTransferFrom} {
+		if string(data[:4]) != string(Selector(signature)) {
+			continue
+		}
+		args, err := DecodeCall(signature, data)
+		if nil != err {
+			return nil, err
+		}
+		call := &TokenCall{Method: signature}
+		switch signature {
+		case SigTransfer:
+			call.To, call.Amount = args[0].(common.Address), args[1].(*big.Int)
+		case SigApprove:
+			call.Spender, call.Amount = args[0].(common.Address), args[1].(*big.Int)
+		case SigTransferFrom:
+			call.From, call.To, call.Amount = args[0].(common.Address), args[1].(common.Address), args[2].(*big.Int)
+			call.TokenId = call.Amount
+		case SigSafeTransferFrom:
+			call.From, call.To, call.TokenId = args[0].(common.Address), args[1].(common.Address), args[2].(*big.Int)
+		case SigSafeTransferFromWithData:
+			call.From, call.To, call.TokenId, call.Data = args[0].(common.Address), args[1].(common.Address), args[2].(*big.Int), args[3].([]byte)
+		case SigERC1155SafeTransferFrom:
+			call.From, call.To, call.TokenId, call.Amount, call.Data = args[0].(common.Address), args[1].(common.Address), args[2].(*big.Int), args[3].(*big.Int), args[4].([]byte)
+		case SigERC1155BatchTransferFrom:
+			call.From, call.To, call.Data = args[0].(common.Address), args[1].(common.Address), args[4].([]byte)
+			call.Ids, call.Amounts = toBigIntList(args[2]), toBigIntList(args[3])
+		}
+		return call, nil
+	}
+	return nil, fmt.Errorf("unknown token selector %x", data[:4])
+}
+
+// TransferLog 解码后的转账事件
+type TransferLog struct {
+	// Standard ERC20、ERC721、ERC1155
+	Standard string         `json:"standard"`
+	Operator common.Address `json:"operator"`
+	From     common.Address `json:"from"`
+	To       common.Address `json:"to"`
+	Amount   *big.Int       `json:"amount"`
+	TokenId  *big.Int       `json:"tokenId"`
+	Ids      []*big.Int     `json:"ids"`
+	Amounts  []*big.Int     `json:"amounts"`
+}
+
+// DecodeTransferLog 解码 Transfer（ERC-20 金额在 data，ERC-721 tokenId 在 topics[3]）、TransferSingle、TransferBatch 事件
+func DecodeTransferLog(topics []common.Hash, data []byte) (*TransferLog, error) {
+	if len(topics) == 0 {
+		return nil, errors.New("log topics miss")
+	}
+	topicAddress := func(h common.Hash) common.Address {
+		return common.BytesToAddress(h.Bytes()[12:])
+	}
+	switch topics[0] {
+	case EventTopic(EventTransfer):
+		if len(topics) == 4 {
+			return &TransferLog{Standard: "ERC721", From: topicAddress(topics[1]), To: topicAddress(topics[2]), TokenId: topics[3].Big()}, nil
+		}
+		if len(topics) != 3 {
+			return nil, errors.New("invalid transfer log topics")
+		}
+		args, err := DecodeArgs([]string{"uint256"}, data)
+		if nil != err {
+			return nil, err
+		}
+		return &TransferLog{Standard: "ERC20", From: topicAddress(topics[1]), To: topicAddress(topics[2]), Amount: args[0].(*big.Int)}, nil
+	case EventTopic(EventTransferSingle):
+		if len(topics) != 4 {
+			return nil, errors.New("invalid transfer single log topics")
+		}
+		args, err := DecodeArgs([]string{"uint256", "uint256"}, data)
+		if nil != err {
+			return nil, err
+		}
+		return &TransferLog{Standard: "ERC1155", Operator: topicAddress(topics[1]), From: topicAddress(topics[2]), To: topicAddress(topics[3]),
+			TokenId: args[0].(*big.Int), Amount: args[1].(*big.Int)}, nil
+	case EventTopic(EventTransferBatch):
+		if len(topics) != 4 {
+			return nil, errors.New("invalid transfer batch log topics")
+		}
+		args, err := DecodeArgs([]string{"uint256[]", "uint256[]"}, data)
+		if nil != err {
+			return nil, err
+		}
+		return &TransferLog{Standard: "ERC1155", Operator: topicAddress(topics[1]), From: topicAddress(topics[2]), To: topicAddress(topics[3]),
+			Ids: toBigIntList(args[0]), Amounts: toBigIntList(args[1])}, nil
+	}
+	return nil, fmt.Errorf("unknown event topic %s", topics[0].Hex())
+}
+
+func toBigIntList(v interface{}) []*big.Int {
+	list := v.([]interface{})
+	out := make([]*big.Int, len(list))
+	for i, n := range list {
+		out[i] = n.(*big.Int)
+	}
+	return out
+}
@@ -0,0 +1,102 @@
+package evmAbi
+
+import (
+	"encoding/hex"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	"testing"
+)
+
+func TestERC20Transfer(t *testing.T) {
+	// TRON hex 地址（41 前缀）与 EVM 地址编码结果一致
+	data, err := ERC20Transfer("41d6b9b86d4bb2af4d8a9d3e2b3b57a0e4b2a2f3c9", big.NewInt(1000000))
+	if nil != err {
+		t.Fatal(err)
+	}
+	expect := "a9059cbb000000000000000000000000d6b9b86d4bb2af4d8a9d3e2b3b57a0e4b2a2f3c900000000000000000000000000000000000000000000000000000000000f4240"
+	if hex.EncodeToString(data) != expect {
+		t.Fatalf("transfer mismatch %x", data)
+	}
+
+	call, err := DecodeTokenCall(data)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if call.Method != SigTransfer || call.To != common.HexToAddress("0xd6b9b86d4bb2af4d8a9d3e2b3b57a0e4b2a2f3c9") || call.Amount.Int64() != 1000000 {
+		t.Fatalf("decode mismatch %+v", call)
+	}
+}
+
+func TestDecodeTokenCall(t *testing.T) {
+	from := common.HexToAddress("0x6ef25ea3f4cceae27d57cbe9a4cfdd2ded2b2740")
+	to := common.HexToAddress("0x19647d4f0f3ea905f635850c8a6745282a6ae1e6")
+
+	data, _ := ERC20Approve(to, big.NewInt(5))
+	if hex.EncodeToString(data[:4]) != "095ea7b3" {
+		t.Fatal("approve selector mismatch")
+	}
+	data, _ = ERC20TransferFrom(from, to, big.NewInt(5))
+	if hex.EncodeToString(data[:4]) != "23b872dd" {
+		t.Fatal("transferFrom selector mismatch")
+	}
+	data, _ = ERC721SafeTransferFrom(from, to, big.NewInt(9), nil)
+	if hex.EncodeToString(data[:4]) != "42842e0e" {
+		t.Fatal("safeTransferFrom selector mismatch")
+	}
+	data, _ = ERC721SafeTransferFrom(from, to, big.NewInt(9), []byte{1})
+	if hex.EncodeToString(data[:4]) != "b88d4fde" {
+		t.Fatal("safeTransferFrom with data selector mismatch")
+	}
+	data, _ = ERC1155SafeTransferFrom(from, to, big.NewInt(1), big.NewInt(2), nil)
+	if hex.EncodeToString(data[:4]) != "f242432a" {
+		t.Fatal("erc1155 safeTransferFrom selector mismatch")
+	}
+	data, err := ERC1155SafeBatchTransferFrom(from, to, []*big.Int{big.NewInt(1), big.NewInt(2)}, []*big.Int{big.NewInt(10), big.NewInt(20)}, []byte("x"))
+	if nil != err {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(data[:4]) != "2eb2c2d6" {
+		t.Fatal("erc1155 safeBatchTransferFrom selector mismatch")
+	}
+	call, err := DecodeTokenCall(data)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if call.From != from || call.To != to || call.Amounts[1].Int64() != 20 || string(call.Data) != "x" {
+		t.Fatalf("decode mismatch %+v", call)
+	}
+}
+
+func TestDecodeTransferLog(t *testing.T) {
+	from := common.HexToAddress("0x6ef25ea3f4cceae27d57cbe9a4cfdd2ded2b2740")
+	to := common.HexToAddress("0x19647d4f0f3ea905f635850c8a6745282a6ae1e6")
+	if EventTopic(EventTransfer).Hex() != "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef" {
+		t.Fatal("transfer topic mismatch")
+	}
+
+	amount, _ := EncodeArgs([]string{"uint256"}, big.NewInt(123))
+	l, err := DecodeTransferLog([]common.Hash{EventTopic(EventTransfer), common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())}, amount)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if l.Standard != "ERC20" || l.From != from || l.To != to || l.Amount.Int64() != 123 {
+		t.Fatalf("erc20 log mismatch %+v", l)
+	}
+
+	l, err = DecodeTransferLog([]common.Hash{EventTopic(EventTransfer), common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes()), common.BigToHash(big.NewInt(77))}, nil)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if l.Standard != "ERC721" || l.TokenId.Int64() != 77 {
+		t.Fatalf("erc721 log mismatch %+v", l)
+	}
+
+	data, _ := EncodeArgs([]string{"uint256[]", "uint256[]"}, []int{1, 2}, []int{3, 4})
+	l, err = DecodeTransferLog([]common.Hash{EventTopic(EventTransferBatch), common.BytesToHash(from.Bytes()), common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())}, data)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if l.Standard != "ERC1155" || len(l.Ids) != 2 || l.Amounts[1].Int64() != 4 {
+		t.Fatalf("erc1155 log mismatch %+v", l)
+	}
+}
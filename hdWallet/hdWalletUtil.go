@@ -7,6 +7,7 @@ import (
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/crypto"
 	hdwallet "github.com/miguelmota/go-ethereum-hdwallet"
+	"sync"
 )
 
 // pathDriveETH 路径(60 eth)
@@ -86,7 +87,21 @@ func (that *hDWalletUtil) WalletPrivateKey(wallet *hdwallet.Wallet, index int) (
 	return wallet.PrivateKey(account)
 }
 
-func GetCoinTypeByNetWork(netWork string) HDCoinType {
+var (
+	netWorkCoinTypeLock sync.RWMutex
+	netWorkCoinTypes    = make(map[string]HDCoinType)
+)
+
+// RegisterNetWorkCoinType 运行时注册网络名称，如自定义的 EVM L2 使用 ETHHDCoinType。
+// ethWal 注册的网络不会自动写入这里，EVM 网络可使用 ethWal.CoinTypeByNetWork 查询
+func RegisterNetWorkCoinType(netWork string, coinType HDCoinType) {
+	netWorkCoinTypeLock.Lock()
+	defer netWorkCoinTypeLock.Unlock()
+	netWorkCoinTypes[netWork] = coinType
+}
+
+// GetCoinTypeByNetWork 网络名称对应的 coinType，先查内置名称再查运行时注册的名称，未知网络返回错误
+func GetCoinTypeByNetWork(netWork string) (HDCoinType, error) {
 	switch netWork {
 	case "BNB":
 		fallthrough
@@ -103,27 +118,32 @@ func GetCoinTypeByNetWork(netWork string) HDCoinType {
 	case "Ethereum":
 		fallthrough
 	case "ETH":
-		return ETHHDCoinType
+		return ETHHDCoinType, nil
 	case "Bitcoin":
 		fallthrough
 	case "BTC":
-		return BTCHDCoinType
+		return BTCHDCoinType, nil
 	case "Litecoin":
 		fallthrough
 	case "LTC":
-		return LTCHDCoinType
+		return LTCHDCoinType, nil
 	case "Dogecoin":
 		fallthrough
 	case "DOGE":
-		return DOGEHDCoinType
+		return DOGEHDCoinType, nil
 	case "TRON":
-		return TRONHDCoinType
+		return TRONHDCoinType, nil
 	case "Solana":
 		fallthrough
 	case "SOL":
-		return SOLHDCoinType
+		return SOLHDCoinType, nil
+	}
+	netWorkCoinTypeLock.RLock()
+	defer netWorkCoinTypeLock.RUnlock()
+	if coinType, ok := netWorkCoinTypes[netWork]; ok {
+		return coinType, nil
 	}
-	panic(fmt.Sprintf("%s not found", netWork))
+	return 0, fmt.Errorf("%s not found", netWork)
 }
 
 // WalletPrivateKeyByCoinType 不同币种 path 不同
@@ -0,0 +1,229 @@
+package hdWallet
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+	"strings"
+)
+
+// keystore 密钥派生方式
+const (
+	KdfScrypt      = "scrypt"       // geth/MetaMask 默认参数 n=262144 r=8 p=1
+	KdfScryptLight = "scrypt-light" // geth --lightkdf 参数 n=4096 r=8 p=6，文件中 kdf 仍为 scrypt
+	KdfPbkdf2      = "pbkdf2"       // hmac-sha256 c=262144
+)
+
+// ErrKeystorePassword 密码错误（mac 校验失败）
+var ErrKeystorePassword = errors.New("could not decrypt key with given password")
+
+const (
+	keystoreVersion = 3
+	keystoreCipher  = "aes-128-ctr"
+	keystoreDkLen   = 32
+)
+
+// Keystore Web3 Secret Storage V3 格式，与 geth、MetaMask 互通
+type Keystore struct {
+	Address string         `json:"address,omitempty"`
+	Crypto  KeystoreCrypto `json:"crypto"`
+	Id      string         `json:"id"`
+	Version int            `json:"version"`
+}
+
+type KeystoreCrypto struct {
+	Cipher       string                 `json:"cipher"`
+	CipherText   string                 `json:"ciphertext"`
+	CipherParams KeystoreCipherParams   `json:"cipherparams"`
+	Kdf          string                 `json:"kdf"`
+	KdfParams    map[string]interface{} `json:"kdfparams"`
+	Mac          string                 `json:"mac"`
+}
+
+type KeystoreCipherParams struct {
+	IV string `json:"iv"`
+}
+
+// EncryptKeystore 私钥加密为 keystore V3 JSON，kdf 为 KdfScrypt、KdfScryptLight 或 KdfPbkdf2，为空时使用 KdfScrypt。
+// address 字段为以太坊格式（不带 0x 的小写 hex），TRON 等同样基于 secp256k1 的私钥也可使用
+func (that *hDWalletUtil) EncryptKeystore(privateKey *ecdsa.PrivateKey, password string, kdf string) ([]byte, error) {
+	salt := make([]byte, 32)
+	iv := make([]byte, aes.BlockSize)
+	id := make([]byte, 16)
+	for _, b := range [][]byte{salt, iv, id} {
+		if _, err := rand.Read(b); nil != err {
+			return nil, err
+		}
+	}
+
+	var kdfParams map[string]interface{}
+	switch kdf {
+	case "", KdfScrypt:
+		kdfParams = map[string]interface{}{"n": 262144, "r": 8, "p": 1}
+	case KdfScryptLight:
+		kdfParams = map[string]interface{}{"n": 4096, "r": 8, "p": 6}
+	case KdfPbkdf2:
+		kdfParams = map[string]interface{}{"c": 262144, "prf": "hmac-sha256"}
+	default:
+		return nil, fmt.Errorf("unsupported kdf: %s", kdf)
+	}
+	kdfParams["dklen"] = keystoreDkLen
+	kdfParams["salt"] = hex.EncodeToString(salt)
+	kdfName := KdfScrypt
+	if kdf == KdfPbkdf2 {
+		kdfName = KdfPbkdf2
+	}
+	derivedKey, err := keystoreDerivedKey(kdfName, kdfParams, password)
+	if nil != err {
+		return nil, err
+	}
+
+	keyBytes := make([]byte, 32)
+	privateKey.D.FillBytes(keyBytes)
+	cipherText, err := aesCTRXOR(derivedKey[:16], keyBytes, iv)
+	if nil != err {
+		return nil, err
+	}
+
+	// uuid v4
+	id[6] = (id[6] & 0x0f) | 0x40
+	id[8] = (id[8] & 0x3f) | 0x80
+	idHex := hex.EncodeToString(id)
+	return json.Marshal(&Keystore{
+		Address: hex.EncodeToString(crypto.PubkeyToAddress(privateKey.PublicKey).Bytes()),
+		Crypto: KeystoreCrypto{
+			Cipher:       keystoreCipher,
+			CipherText:   hex.EncodeToString(cipherText),
+			CipherParams: KeystoreCipherParams{IV: hex.EncodeToString(iv)},
+			Kdf:          kdfName,
+			KdfParams:    kdfParams,
+			Mac:          hex.EncodeToString(crypto.Keccak256(derivedKey[16:32], cipherText)),
+		},
+		Id:      fmt.Sprintf("%s-%s-%s-%s-%s", idHex[:8], idHex[8:12], idHex[12:16], idHex[16:20], idHex[20:]),
+		Version: keystoreVersion,
+	})
+}
+
+// DecryptKeystore 解密 keystore V3 JSON，支持 scrypt 与 pbkdf2，文件带 address 时校验是否与私钥一致
+func (that *hDWalletUtil) DecryptKeystore(keystoreJson []byte, password string) (*ecdsa.PrivateKey, error) {
+	keystore := new(Keystore)
+	if err := json.Unmarshal(keystoreJson, keystore); nil != err {
+		return nil, fmt.Errorf("unmarshal keystore error: %v", err)
+	}
+	if keystore.Version != keystoreVersion {
+		return nil, fmt.Errorf("unsupported keystore version: %d", keystore.Version)
+	}
+	if keystore.Crypto.Cipher != keystoreCipher {
+		return nil, fmt.Errorf("unsupported cipher: %s", keystore.Crypto.Cipher)
+	}
+	mac, err := hex.DecodeString(keystore.Crypto.Mac)
+	if nil != err {
+		return nil, fmt.Errorf("decode mac error: %v", err)
+	}
+	iv, err := hex.DecodeString(keystore.Crypto.CipherParams.IV)
+	if nil != err {
+		return nil, fmt.Errorf("decode iv error: %v", err)
+	}
+	cipherText, err := hex.DecodeString(keystore.Crypto.CipherText)
+	if nil != err {
+		return nil, fmt.Errorf("decode ciphertext error: %v", err)
+	}
+	derivedKey, err := keystoreDerivedKey(keystore.Crypto.Kdf, keystore.Crypto.KdfParams, password)
+	if nil != err {
+		return nil, err
+	}
+	if !bytes.Equal(crypto.Keccak256(derivedKey[16:32], cipherText), mac) {
+		return nil, ErrKeystorePassword
+	}
+	keyBytes, err := aesCTRXOR(derivedKey[:16], cipherText, iv)
+	if nil != err {
+		return nil, err
+	}
+	privateKey, err := crypto.ToECDSA(keyBytes)
+	if nil != err {
+		return nil, fmt.Errorf("invalid private key: %v", err)
+	}
+	if keystore.Address != "" {
+		address := hex.EncodeToString(crypto.PubkeyToAddress(privateKey.PublicKey).Bytes())
+		if !strings.EqualFold(strings.TrimPrefix(keystore.Address, "0x"), address) {
+			return nil, fmt.Errorf("keystore address %s mismatch private key address %s", keystore.Address, address)
+		}
+	}
+	return privateKey, nil
+}
+
+func keystoreDerivedKey(kdf string, params map[string]interface{}, password string) ([]byte, error) {
+	getInt := func(name string) (int, error) {
+		v, ok := params[name].(float64)
+		if !ok {
+			if i, isInt := params[name].(int); isInt {
+				return i, nil
+			}
+			return 0, fmt.Errorf("kdfparams miss %s", name)
+		}
+		return int(v), nil
+	}
+	saltHex, _ := params["salt"].(string)
+	salt, err := hex.DecodeString(saltHex)
+	if nil != err {
+		return nil, fmt.Errorf("decode salt error: %v", err)
+	}
+	dkLen, err := getInt("dklen")
+	if nil != err {
+		return nil, err
+	}
+	if dkLen < 32 {
+		return nil, fmt.Errorf("invalid dklen: %d", dkLen)
+	}
+
+	switch kdf {
+	case KdfScrypt:
+		n, err := getInt("n")
+		if nil != err {
+			return nil, err
+		}
+		r, err := getInt("r")
+		if nil != err {
+			return nil, err
+		}
+		p, err := getInt("p")
+		if nil != err {
+			return nil, err
+		}
+		return scrypt.Key([]byte(password), salt, n, r, p, dkLen)
+	case KdfPbkdf2:
+		if prf, _ := params["prf"].(string); prf != "hmac-sha256" {
+			return nil, fmt.Errorf("unsupported pbkdf2 prf: %s", prf)
+		}
+		c, err := getInt("c")
+		if nil != err {
+			return nil, err
+		}
+		return pbkdf2.Key([]byte(password), salt, c, dkLen, sha256.New), nil
+	}
+	return nil, fmt.Errorf("unsupported kdf: %s", kdf)
+}
+
+func aesCTRXOR(key, input, iv []byte) ([]byte, error) {
+	// cipher.NewCTR 在 iv 长度不等于分组长度时 panic
+	if len(iv) != aes.BlockSize {
+		return nil, fmt.Errorf("invalid iv length: %d", len(iv))
+	}
+	block, err := aes.NewCipher(key)
+	if nil != err {
+		return nil, err
+	}
+	output := make([]byte, len(input))
+	cipher.NewCTR(block, iv).XORKeyStream(output, input)
+	return output, nil
+}
@@ -0,0 +1,57 @@
+package hdWallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"github.com/ethereum/go-ethereum/crypto"
+	"strings"
+	"testing"
+)
+
+// Web3 Secret Storage 规范中的测试向量，密码 testpassword
+func TestDecryptKeystore(t *testing.T) {
+	vectors := []string{
+		`{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"6087dab2f9fdbbfaddc31a909735c1e6"},"ciphertext":"5318b4d5bcd28de64ee5559e671353e16f075ecae9f99c7a79a38af5f869aa46","kdf":"pbkdf2","kdfparams":{"c":262144,"dklen":32,"prf":"hmac-sha256","salt":"ae3cd4e7013836a3df6bd7241b12db061dbe2c6785853cce422d148a624ce0bd"},"mac":"517ead924a9d0dc3124507e3393d175ce3ff7c1e96529c6c555ce9e51205e9b2"},"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`,
+		`{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"83dbcc02d8ccb40e466191a123791e0e"},"ciphertext":"d172bf743a674da9cdad04534d56926ef8358534d458fffccd4e6ad2fbde479c","kdf":"scrypt","kdfparams":{"dklen":32,"n":262144,"r":1,"p":8,"salt":"ab0c7876052600dd703518d6fc3fe8984592145b591fc8fb5c6d43190334ba19"},"mac":"2103ac29920d71da29f15d75b4a16dbe95cfd7ff8faea1056c33131d846e3097"},"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`,
+	}
+	for _, vector := range vectors {
+		privateKey, err := GetInstanceByHDWalletUtil().DecryptKeystore([]byte(vector), "testpassword")
+		if nil != err {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(crypto.FromECDSA(privateKey)) != "7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d" {
+			t.Fatalf("private key mismatch %x", crypto.FromECDSA(privateKey))
+		}
+	}
+	if _, err := GetInstanceByHDWalletUtil().DecryptKeystore([]byte(vectors[0]), "wrong"); !errors.Is(err, ErrKeystorePassword) {
+		t.Fatalf("expected password error, got %v", err)
+	}
+	// mac 不覆盖 iv，篡改 iv 长度不能 panic
+	shortIv := strings.Replace(vectors[0], "6087dab2f9fdbbfaddc31a909735c1e6", "6087dab2", 1)
+	if _, err := GetInstanceByHDWalletUtil().DecryptKeystore([]byte(shortIv), "testpassword"); nil == err {
+		t.Fatal("expected iv length error")
+	}
+}
+
+func TestEncryptKeystore(t *testing.T) {
+	privateKey, err := GetInstanceByHDWalletUtil().LoadWalletByPrivateKey("1ea107cf1e8cbca5a1e9ee2661505b1836db495c574eace64ecdbc20b29b83fd")
+	if nil != err {
+		t.Fatal(err)
+	}
+	for _, kdf := range []string{KdfScryptLight, KdfPbkdf2} {
+		keystoreJson, err := GetInstanceByHDWalletUtil().EncryptKeystore(privateKey, "pass", kdf)
+		if nil != err {
+			t.Fatal(err)
+		}
+		decrypted, err := GetInstanceByHDWalletUtil().DecryptKeystore(keystoreJson, "pass")
+		if nil != err {
+			t.Fatal(err)
+		}
+		if decrypted.D.Cmp(privateKey.D) != 0 {
+			t.Fatalf("%s round trip mismatch", kdf)
+		}
+	}
+	if _, err := GetInstanceByHDWalletUtil().EncryptKeystore(privateKey, "pass", "argon2"); nil == err {
+		t.Fatal("expected unsupported kdf")
+	}
+}
@@ -27,6 +27,8 @@ type PrevOutput struct {
 	Address    string `json:"address"`
 	PrivateKey string `json:"privateKey"`
 	PublicKey  string `json:"publicKey"`
+	// Assets 该 UTXO 上的铭文/符文，携带资产的 UTXO 不能用于支付 commit 交易
+	Assets *UtxoAssets `json:"assets,omitempty"`
 }
 
 type PrevOutputs []*PrevOutput
@@ -58,10 +60,13 @@ type InscriptionRequest struct {
 	RevealOutValue         int64             `json:"revealOutValue"`
 	ChangeAddress          string            `json:"changeAddress"`
 	MinChangeValue         int64             `json:"minChangeValue"`
+	// BatchReveal 为 true 时所有铭文放入同一笔 reveal 交易，按顺序落在各个 reveal 输出上
+	BatchReveal bool `json:"batchReveal"`
 }
 
 type inscriptionTxCtxData struct {
 	PrivateKey              *btcec.PrivateKey
+	PublicKey               *btcec.PublicKey
 	InscriptionScript       []byte
 	CommitTxAddress         string
 	CommitTxAddressPkScript []byte
@@ -81,6 +86,9 @@ type InscriptionBuilder struct {
 	MustCommitTxFee           int64
 	MustRevealTxFees          []int64
 	CommitAddrs               []string
+	// request、revealPubKey 构建时的参数，CalculateFeeComparison 据此重建另一种 reveal 模式
+	request      *InscriptionRequest
+	revealPubKey *btcec.PublicKey
 }
 
 type InscribeTxs struct {
@@ -112,7 +120,84 @@ const (
 	OrdPrefix = "ord"
 )
 
+func NewInscriptionTool(network *chaincfg.Params, request *InscriptionRequest) (*InscriptionBuilder, error) {
+	commitTxPrivateKeyList := make([]*btcec.PrivateKey, len(request.CommitTxPrevOutputList))
+	for i := 0; i < len(request.CommitTxPrevOutputList); i++ {
+		privateKeyWif, err := btcutil.DecodeWIF(request.CommitTxPrevOutputList[i].PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		commitTxPrivateKeyList[i] = privateKeyWif.PrivKey
+	}
+	builder := newInscriptionBuilder(network, request)
+	builder.CommitTxPrivateKeyList = commitTxPrivateKeyList
+	if err := builder.initTool(network, request); err != nil {
+		return nil, err
+	}
+	return builder, nil
+}
+
+func newInscriptionBuilder(network *chaincfg.Params, request *InscriptionRequest) *InscriptionBuilder {
+	return &InscriptionBuilder{
+		Network:                   network,
+		CommitTxPrevOutputFetcher: txscript.NewMultiPrevOutFetcher(nil),
+		RevealTxPrevOutputFetcher: txscript.NewMultiPrevOutFetcher(nil),
+		CommitTxPrevOutputList:    request.CommitTxPrevOutputList,
+	}
+}
+
+func Inscribe(network *chaincfg.Params, request *InscriptionRequest) (*InscribeTxs, error) {
+	builder, err := NewInscriptionTool(network, request)
+	if err != nil {
+		return nil, err
+	}
+	return builder.inscribeTxs()
+}
+
+func (builder *InscriptionBuilder) inscribeTxs() (*InscribeTxs, error) {
+	commitTx, err := builder.GetCommitTxHex()
+	if err != nil {
+		return nil, err
+	}
+	revealTxs, err := builder.GetRevealTxHexList()
+	if err != nil {
+		return nil, err
+	}
+	commitTxFee, revealTxFees := builder.CalculateFee()
+	return &InscribeTxs{
+		CommitTx:     commitTx,
+		RevealTxs:    revealTxs,
+		CommitTxFee:  commitTxFee,
+		RevealTxFees: revealTxFees,
+		CommitAddrs:  builder.CommitAddrs,
+	}, nil
+}
+
 func (builder *InscriptionBuilder) initTool(network *chaincfg.Params, request *InscriptionRequest) error {
+	privateKeyWif, err := btcutil.DecodeWIF(request.CommitTxPrevOutputList[0].PrivateKey)
+	if err != nil {
+		return err
+	}
+	if err = builder.buildUnsignedTxs(network, request, privateKeyWif.PrivKey.PubKey()); err != nil {
+		return err
+	}
+	for i := range builder.InscriptionTxCtxDataList {
+		builder.InscriptionTxCtxDataList[i].PrivateKey = privateKeyWif.PrivKey
+	}
+	err = builder.signCommitTx()
+	if err != nil {
+		return errors.New("sign commit tx error")
+	}
+	err = builder.completeRevealTx()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// buildUnsignedTxs 构建未签名的 commit 与 reveal 交易，revealPubKey 为 reveal 脚本与 taproot 内部公钥
+func (builder *InscriptionBuilder) buildUnsignedTxs(network *chaincfg.Params, request *InscriptionRequest, revealPubKey *btcec.PublicKey) error {
+	builder.request, builder.revealPubKey = request, revealPubKey
 	destinations := make([]string, len(request.InscriptionDataList))
 	revealOutValue := DefaultRevealOutValue
 	if request.RevealOutValue > 0 {
@@ -123,79 +208,124 @@ func (builder *InscriptionBuilder) initTool(network *chaincfg.Params, request *I
 		minChangeValue = request.MinChangeValue
 	}
 	for i := 0; i < len(request.InscriptionDataList); i++ {
-		inscriptionTxCtxData, err := newInscriptionTxCtxData(network, request, i)
-		if err != nil {
-			return err
-		}
-		builder.InscriptionTxCtxDataList[i] = inscriptionTxCtxData
 		destinations[i] = request.InscriptionDataList[i].RevealAddr
 	}
-	totalRevealPrevOutputValue, err := builder.buildEmptyRevealTx(destinations, revealOutValue, request.RevealFeeRate)
+	inscriptionTxCtxDataList, err := newInscriptionTxCtxDataList(network, request, revealPubKey, revealOutValue)
 	if err != nil {
 		return err
 	}
-	err = builder.buildCommitTx(request.CommitTxPrevOutputList, request.ChangeAddress, totalRevealPrevOutputValue, request.CommitFeeRate, minChangeValue)
+	builder.InscriptionTxCtxDataList = inscriptionTxCtxDataList
+	totalRevealPrevOutputValue, err := builder.buildEmptyRevealTx(destinations, revealOutValue, request.RevealFeeRate, request.BatchReveal)
 	if err != nil {
 		return err
 	}
-	err = builder.signCommitTx()
-	if err != nil {
-		return errors.New("sign commit tx error")
+	return builder.buildCommitTx(request.CommitTxPrevOutputList, request.ChangeAddress, totalRevealPrevOutputValue, request.CommitFeeRate, minChangeValue)
+}
+
+// newInscriptionTxCtxDataList 逐个模式下每个铭文一个 commit 输出，批量模式下所有铭文共用一个
+func newInscriptionTxCtxDataList(network *chaincfg.Params, request *InscriptionRequest, revealPubKey *btcec.PublicKey, revealOutValue int64) ([]*inscriptionTxCtxData, error) {
+	if request.BatchReveal {
+		inscriptionScript, err := newInscriptionScript(revealPubKey, request.InscriptionDataList, revealOutValue)
+		if err != nil {
+			return nil, err
+		}
+		ctxData, err := newInscriptionTxCtxData(network, revealPubKey, inscriptionScript)
+		if err != nil {
+			return nil, err
+		}
+		return []*inscriptionTxCtxData{ctxData}, nil
 	}
-	err = builder.completeRevealTx()
-	if err != nil {
-		return err
+	inscriptionTxCtxDataList := make([]*inscriptionTxCtxData, len(request.InscriptionDataList))
+	for i := 0; i < len(request.InscriptionDataList); i++ {
+		inscriptionScript, err := newInscriptionScript(revealPubKey, request.InscriptionDataList[i:i+1], revealOutValue)
+		if err != nil {
+			return nil, err
+		}
+		ctxData, err := newInscriptionTxCtxData(network, revealPubKey, inscriptionScript)
+		if err != nil {
+			return nil, err
+		}
+		inscriptionTxCtxDataList[i] = ctxData
 	}
-	return nil
+	return inscriptionTxCtxDataList, nil
 }
 
-func newInscriptionTxCtxData(network *chaincfg.Params, inscriptionRequest *InscriptionRequest, indexOfInscriptionDataList int) (*inscriptionTxCtxData, error) {
-	privateKeyWif, err := btcutil.DecodeWIF(inscriptionRequest.CommitTxPrevOutputList[0].PrivateKey)
+// newInscriptionScript 生成 reveal 脚本，批量模式下多个铭文信封共用一个脚本，
+// 第 i 个铭文通过 pointer 指向第 i 个 reveal 输出的首个 sat
+func newInscriptionScript(publicKey *btcec.PublicKey, inscriptionDataList []InscriptionData, revealOutValue int64) ([]byte, error) {
+	inscriptionScript, err := txscript.NewScriptBuilder().
+		AddData(schnorr.SerializePubKey(publicKey)).
+		AddOp(txscript.OP_CHECKSIG).
+		Script()
 	if err != nil {
 		return nil, err
 	}
-	privateKey := privateKeyWif.PrivKey
+	for i := range inscriptionDataList {
+		envelope, err := newInscriptionEnvelope(&inscriptionDataList[i], int64(i)*revealOutValue)
+		if err != nil {
+			return nil, err
+		}
+		inscriptionScript = append(inscriptionScript, envelope...)
+	}
+	return inscriptionScript, nil
+}
 
+func newInscriptionEnvelope(inscriptionData *InscriptionData, pointer int64) ([]byte, error) {
 	inscriptionBuilder := txscript.NewScriptBuilder().
-		AddData(schnorr.SerializePubKey(privateKey.PubKey())).
-		AddOp(txscript.OP_CHECKSIG).
 		AddOp(txscript.OP_FALSE).
 		AddOp(txscript.OP_IF).
 		AddData([]byte(OrdPrefix)).
 		AddOp(txscript.OP_DATA_1).
 		AddOp(txscript.OP_DATA_1).
-		AddData([]byte(inscriptionRequest.InscriptionDataList[indexOfInscriptionDataList].ContentType)).
-		AddOp(txscript.OP_0)
+		AddData([]byte(inscriptionData.ContentType))
+	if pointer > 0 {
+		// tag 2: pointer，小端序并去掉末尾的 0
+		inscriptionBuilder.AddOp(txscript.OP_DATA_1).
+			AddOp(txscript.OP_DATA_2).
+			AddFullData(encodeInscriptionPointer(pointer))
+	}
+	inscriptionBuilder.AddOp(txscript.OP_0)
 	maxChunkSize := 520
 	// use taproot to skip txscript.MaxScriptSize 10000
-	bodySize := len(inscriptionRequest.InscriptionDataList[indexOfInscriptionDataList].Body)
+	bodySize := len(inscriptionData.Body)
 	for i := 0; i < bodySize; i += maxChunkSize {
 		end := i + maxChunkSize
 		if end > bodySize {
 			end = bodySize
 		}
 
-		inscriptionBuilder.AddFullData(inscriptionRequest.InscriptionDataList[indexOfInscriptionDataList].Body[i:end])
+		inscriptionBuilder.AddFullData(inscriptionData.Body[i:end])
 	}
-	inscriptionScript, err := inscriptionBuilder.Script()
+	envelope, err := inscriptionBuilder.Script()
 	if err != nil {
 		return nil, err
 	}
-	inscriptionScript = append(inscriptionScript, txscript.OP_ENDIF)
+	return append(envelope, txscript.OP_ENDIF), nil
+}
+
+func encodeInscriptionPointer(pointer int64) []byte {
+	b := make([]byte, 0, 8)
+	for pointer > 0 {
+		b = append(b, byte(pointer))
+		pointer >>= 8
+	}
+	return b
+}
 
+func newInscriptionTxCtxData(network *chaincfg.Params, publicKey *btcec.PublicKey, inscriptionScript []byte) (*inscriptionTxCtxData, error) {
 	proof := &txscript.TapscriptProof{
-		TapLeaf:  txscript.NewBaseTapLeaf(schnorr.SerializePubKey(privateKey.PubKey())),
+		TapLeaf:  txscript.NewBaseTapLeaf(schnorr.SerializePubKey(publicKey)),
 		RootNode: txscript.NewBaseTapLeaf(inscriptionScript),
 	}
 
-	controlBlock := proof.ToControlBlock(privateKey.PubKey())
+	controlBlock := proof.ToControlBlock(publicKey)
 	controlBlockWitness, err := controlBlock.ToBytes()
 	if err != nil {
 		return nil, err
 	}
 
 	tapHash := proof.RootNode.TapHash()
-	commitTxAddress, err := btcutil.NewAddressTaproot(schnorr.SerializePubKey(txscript.ComputeTaprootOutputKey(privateKey.PubKey(), tapHash[:])), network)
+	commitTxAddress, err := btcutil.NewAddressTaproot(schnorr.SerializePubKey(txscript.ComputeTaprootOutputKey(publicKey, tapHash[:])), network)
 	if err != nil {
 		return nil, err
 	}
@@ -205,7 +335,7 @@ func newInscriptionTxCtxData(network *chaincfg.Params, inscriptionRequest *Inscr
 	}
 
 	return &inscriptionTxCtxData{
-		PrivateKey:              privateKey,
+		PublicKey:               publicKey,
 		InscriptionScript:       inscriptionScript,
 		CommitTxAddress:         commitTxAddress.EncodeAddress(),
 		CommitTxAddressPkScript: commitTxAddressPkScript,
@@ -213,17 +343,23 @@ func newInscriptionTxCtxData(network *chaincfg.Params, inscriptionRequest *Inscr
 	}, nil
 }
 
-func (builder *InscriptionBuilder) buildEmptyRevealTx(destination []string, revealOutValue, revealFeeRate int64) (int64, error) {
+func (builder *InscriptionBuilder) buildEmptyRevealTx(destination []string, revealOutValue, revealFeeRate int64, batchReveal bool) (int64, error) {
 	addTxInTxOutIntoRevealTx := func(tx *wire.MsgTx, index int) error {
 		in := wire.NewTxIn(&wire.OutPoint{Index: uint32(index)}, nil, nil)
 		in.Sequence = DefaultSequenceNum
 		tx.AddTxIn(in)
-		scriptPubKey, err := AddrToPkScript(destination[index], builder.Network)
-		if err != nil {
-			return err
+		outDestination := destination[index : index+1]
+		if batchReveal {
+			outDestination = destination
+		}
+		for _, addr := range outDestination {
+			scriptPubKey, err := AddrToPkScript(addr, builder.Network)
+			if err != nil {
+				return err
+			}
+			out := wire.NewTxOut(revealOutValue, scriptPubKey)
+			tx.AddTxOut(out)
 		}
-		out := wire.NewTxOut(revealOutValue, scriptPubKey)
-		tx.AddTxOut(out)
 		return nil
 	}
 
@@ -238,7 +374,7 @@ func (builder *InscriptionBuilder) buildEmptyRevealTx(destination []string, reve
 		if err != nil {
 			return 0, err
 		}
-		prevOutputValue := revealOutValue + int64(tx.SerializeSize())*revealFeeRate
+		prevOutputValue := revealOutValue*int64(len(tx.TxOut)) + int64(tx.SerializeSize())*revealFeeRate
 		emptySignature := make([]byte, 64)
 		emptyControlBlockWitness := make([]byte, 33)
 		fee := (int64(wire.TxWitness{emptySignature, builder.InscriptionTxCtxDataList[i].InscriptionScript, emptyControlBlockWitness}.SerializeSize()+2+3) / 4) * revealFeeRate
@@ -260,6 +396,9 @@ func (builder *InscriptionBuilder) buildEmptyRevealTx(destination []string, reve
 }
 
 func (builder *InscriptionBuilder) buildCommitTx(commitTxPrevOutputList PrevOutputs, changeAddress string, totalRevealPrevOutputValue, commitFeeRate int64, minChangeValue int64) error {
+	if err := commitTxPrevOutputList.checkCardinal(); err != nil {
+		return err
+	}
 	totalSenderAmount := btcutil.Amount(0)
 	tx := wire.NewMsgTx(DefaultTxVersion)
 	changePkScript, err := AddrToPkScript(changeAddress, builder.Network)
@@ -294,7 +433,7 @@ func (builder *InscriptionBuilder) buildCommitTx(commitTxPrevOutputList PrevOutp
 	txForEstimate := wire.NewMsgTx(DefaultTxVersion)
 	txForEstimate.TxIn = tx.TxIn
 	txForEstimate.TxOut = tx.TxOut
-	if err = Sign(txForEstimate, builder.CommitTxPrivateKeyList, builder.CommitTxPrevOutputFetcher); err != nil {
+	if err = builder.signForEstimate(txForEstimate, commitTxPrevOutputList); err != nil {
 		return err
 	}
 
@@ -319,17 +458,9 @@ func (builder *InscriptionBuilder) buildCommitTx(commitTxPrevOutputList PrevOutp
 }
 
 func (builder *InscriptionBuilder) completeRevealTx() error {
+	builder.linkRevealTx()
 	for i := range builder.InscriptionTxCtxDataList {
-		builder.RevealTxPrevOutputFetcher.AddPrevOut(wire.OutPoint{
-			Hash:  builder.CommitTx.TxHash(),
-			Index: uint32(i),
-		}, builder.InscriptionTxCtxDataList[i].RevealTxPrevOutput)
-		builder.RevealTx[i].TxIn[0].PreviousOutPoint.Hash = builder.CommitTx.TxHash()
-	}
-	for i := range builder.InscriptionTxCtxDataList {
-		revealTx := builder.RevealTx[i]
-		witnessArray, err := txscript.CalcTapscriptSignaturehash(txscript.NewTxSigHashes(revealTx, builder.RevealTxPrevOutputFetcher),
-			txscript.SigHashDefault, revealTx, 0, builder.RevealTxPrevOutputFetcher, txscript.NewBaseTapLeaf(builder.InscriptionTxCtxDataList[i].InscriptionScript))
+		witnessArray, err := builder.revealTxSigHash(i)
 		if err != nil {
 			return err
 		}
@@ -337,9 +468,35 @@ func (builder *InscriptionBuilder) completeRevealTx() error {
 		if err != nil {
 			return err
 		}
-		witness := wire.TxWitness{signature.Serialize(), builder.InscriptionTxCtxDataList[i].InscriptionScript, builder.InscriptionTxCtxDataList[i].ControlBlockWitness}
-		builder.RevealTx[i].TxIn[0].Witness = witness
+		builder.setRevealTxWitness(i, signature.Serialize())
 	}
+	return builder.checkRevealTxWeight()
+}
+
+// linkRevealTx 将 reveal 交易的输入指向 commit 交易的输出
+func (builder *InscriptionBuilder) linkRevealTx() {
+	for i := range builder.InscriptionTxCtxDataList {
+		builder.RevealTxPrevOutputFetcher.AddPrevOut(wire.OutPoint{
+			Hash:  builder.CommitTx.TxHash(),
+			Index: uint32(i),
+		}, builder.InscriptionTxCtxDataList[i].RevealTxPrevOutput)
+		builder.RevealTx[i].TxIn[0].PreviousOutPoint.Hash = builder.CommitTx.TxHash()
+	}
+}
+
+// revealTxSigHash reveal 交易脚本路径花费的 BIP341 sighash
+func (builder *InscriptionBuilder) revealTxSigHash(index int) ([]byte, error) {
+	revealTx := builder.RevealTx[index]
+	return txscript.CalcTapscriptSignaturehash(txscript.NewTxSigHashes(revealTx, builder.RevealTxPrevOutputFetcher),
+		txscript.SigHashDefault, revealTx, 0, builder.RevealTxPrevOutputFetcher, txscript.NewBaseTapLeaf(builder.InscriptionTxCtxDataList[index].InscriptionScript))
+}
+
+func (builder *InscriptionBuilder) setRevealTxWitness(index int, signature []byte) {
+	witness := wire.TxWitness{signature, builder.InscriptionTxCtxDataList[index].InscriptionScript, builder.InscriptionTxCtxDataList[index].ControlBlockWitness}
+	builder.RevealTx[index].TxIn[0].Witness = witness
+}
+
+func (builder *InscriptionBuilder) checkRevealTxWeight() error {
 	// check tx max tx wight
 	for i, tx := range builder.RevealTx {
 		revealWeight := GetTransactionWeight(btcutil.NewTx(tx))
@@ -354,6 +511,14 @@ func (builder *InscriptionBuilder) signCommitTx() error {
 	return Sign(builder.CommitTx, builder.CommitTxPrivateKeyList, builder.CommitTxPrevOutputFetcher)
 }
 
+// signForEstimate 有私钥时真实签名，MPC 模式下没有私钥则填充占位签名，仅用于估算手续费
+func (builder *InscriptionBuilder) signForEstimate(tx *wire.MsgTx, prevOutputs PrevOutputs) error {
+	if len(builder.CommitTxPrivateKeyList) == len(tx.TxIn) {
+		return Sign(tx, builder.CommitTxPrivateKeyList, builder.CommitTxPrevOutputFetcher)
+	}
+	return fillEstimateWitness(tx, prevOutputs, builder.CommitTxPrevOutputFetcher)
+}
+
 func Sign(tx *wire.MsgTx, privateKeys []*btcec.PrivateKey, prevOutFetcher *txscript.MultiPrevOutFetcher) error {
 	for i, in := range tx.TxIn {
 		prevOut := prevOutFetcher.FetchPrevOutput(in.PreviousOutPoint)
@@ -432,15 +597,84 @@ func (builder *InscriptionBuilder) CalculateFee() (int64, []int64) {
 	revealTxFees := make([]int64, 0)
 	for _, tx := range builder.RevealTx {
 		revealTxFee := int64(0)
-		for i, in := range tx.TxIn {
+		for _, in := range tx.TxIn {
 			revealTxFee += builder.RevealTxPrevOutputFetcher.FetchPrevOutput(in.PreviousOutPoint).Value
-			revealTxFee -= tx.TxOut[i].Value
-			revealTxFees = append(revealTxFees, revealTxFee)
 		}
+		for _, out := range tx.TxOut {
+			revealTxFee -= out.Value
+		}
+		revealTxFees = append(revealTxFees, revealTxFee)
 	}
 	return commitTxFee, revealTxFees
 }
 
+type InscribeFee struct {
+	CommitTxFee  int64   `json:"commitTxFee"`
+	RevealTxFees []int64 `json:"revealTxFees"`
+	TotalFee     int64   `json:"totalFee"`
+}
+
+type InscribeFeeComparison struct {
+	PerItem *InscribeFee `json:"perItem"`
+	Batch   *InscribeFee `json:"batch"`
+}
+
+// CalculateFeeComparison 在 CalculateFee 的基础上比较逐个 reveal 与批量 reveal 两种模式的手续费，
+// 当前模式直接取 CalculateFee，另一种模式用相同参数重建未签名交易计算
+func (builder *InscriptionBuilder) CalculateFeeComparison() (*InscribeFeeComparison, error) {
+	if builder.request == nil {
+		return nil, errors.New("inscription builder not built")
+	}
+	other := *builder.request
+	other.BatchReveal = !builder.request.BatchReveal
+	otherBuilder := newInscriptionBuilder(builder.Network, &other)
+	if err := otherBuilder.buildUnsignedTxs(builder.Network, &other, builder.revealPubKey); err != nil {
+		return nil, err
+	}
+	otherBuilder.linkRevealTx()
+	if builder.request.BatchReveal {
+		return &InscribeFeeComparison{PerItem: otherBuilder.inscribeFee(), Batch: builder.inscribeFee()}, nil
+	}
+	return &InscribeFeeComparison{PerItem: builder.inscribeFee(), Batch: otherBuilder.inscribeFee()}, nil
+}
+
+func (builder *InscriptionBuilder) inscribeFee() *InscribeFee {
+	commitTxFee, revealTxFees := builder.CalculateFee()
+	fee := &InscribeFee{CommitTxFee: commitTxFee, RevealTxFees: revealTxFees, TotalFee: commitTxFee}
+	for _, v := range revealTxFees {
+		fee.TotalFee += v
+	}
+	return fee
+}
+
+// CalculateInscribeFee 无需签名即可比较两种模式的手续费，构建未签名交易后调用 CalculateFeeComparison。
+// reveal 公钥取第一个输入的私钥，没有私钥时取其 PublicKey
+func CalculateInscribeFee(network *chaincfg.Params, request *InscriptionRequest) (*InscribeFeeComparison, error) {
+	if len(request.CommitTxPrevOutputList) == 0 {
+		return nil, errors.New("commit tx prev output miss")
+	}
+	var revealPubKey *btcec.PublicKey
+	if request.CommitTxPrevOutputList[0].PrivateKey != "" {
+		privateKeyWif, err := btcutil.DecodeWIF(request.CommitTxPrevOutputList[0].PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		revealPubKey = privateKeyWif.PrivKey.PubKey()
+	} else {
+		pubKey, err := parseRevealPublicKey(request.CommitTxPrevOutputList[0].PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		revealPubKey = pubKey
+	}
+	builder := newInscriptionBuilder(network, request)
+	if err := builder.buildUnsignedTxs(network, request, revealPubKey); err != nil {
+		return nil, err
+	}
+	builder.linkRevealTx()
+	return builder.CalculateFeeComparison()
+}
+
 // GetTransactionWeight computes the value of the weight metric for a given
 // transaction. Currently the weight metric is simply the sum of the
 // transactions's serialized size without any witness data scaled
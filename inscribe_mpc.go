@@ -0,0 +1,267 @@
+package txBuilder
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// InscribeForMPCUnsigned MPC 铭文第一阶段：构建未签名的 commit/reveal 交易，返回每个输入待签名的 sighash。
+// SigHashList 顺序为 commit 交易的输入（P2WPKH/P2SH-P2WPKH 为 BIP143，P2TR 为 BIP341 key path），
+// 之后依次是每笔 reveal 交易（BIP341 script path，使用 revealPublicKey 对应私钥的 schnorr 签名，不做 tweak）。
+// revealPublicKey 为 33 字节压缩公钥或 32 字节 x-only 公钥的 hex，commit 输入需提供 PublicKey（P2TR 除外）。
+func InscribeForMPCUnsigned(network *chaincfg.Params, request *InscriptionRequest, revealPublicKey string) (*InscribeForMPCRes, error) {
+	builder, err := newInscriptionToolForMPC(network, request, revealPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	sigHashList, err := builder.mpcSigHashList()
+	if err != nil {
+		return nil, err
+	}
+	txs, err := builder.inscribeTxs()
+	if err != nil {
+		return nil, err
+	}
+	return &InscribeForMPCRes{
+		SigHashList:  sigHashList,
+		CommitTx:     txs.CommitTx,
+		RevealTxs:    txs.RevealTxs,
+		CommitTxFee:  txs.CommitTxFee,
+		RevealTxFees: txs.RevealTxFees,
+		CommitAddrs:  txs.CommitAddrs,
+	}, nil
+}
+
+// InscribeForMPCSigned MPC 铭文第二阶段：使用与第一阶段相同的参数重建交易，按 SigHashList 的顺序填入签名。
+// commit 输入的 ECDSA 签名为 DER 编码（不含 sighash type），schnorr 签名为 64 字节。
+func InscribeForMPCSigned(network *chaincfg.Params, request *InscriptionRequest, revealPublicKey string, signatures []string) (*InscribeTxs, error) {
+	builder, err := newInscriptionToolForMPC(network, request, revealPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	sigHashList, err := builder.mpcSigHashList()
+	if err != nil {
+		return nil, err
+	}
+	if len(signatures) != len(sigHashList) {
+		return nil, fmt.Errorf("signature miss, need %d got %d", len(sigHashList), len(signatures))
+	}
+
+	commitTx := builder.CommitTx
+	for i, in := range commitTx.TxIn {
+		prevOut := builder.CommitTxPrevOutputFetcher.FetchPrevOutput(in.PreviousOutPoint)
+		sigHash, _ := hex.DecodeString(sigHashList[i])
+		signature, err := DecodeHexString(signatures[i])
+		if err != nil {
+			return nil, err
+		}
+		if txscript.IsPayToTaproot(prevOut.PkScript) {
+			if err = verifySchnorrSignature(signature, sigHash, prevOut.PkScript[2:]); err != nil {
+				return nil, fmt.Errorf("commit input %d: %v", i, err)
+			}
+			in.Witness = wire.TxWitness{signature}
+			continue
+		}
+		pubKeyBytes, err := DecodeHexString(builder.CommitTxPrevOutputList[i].PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		pubKey, err := btcec.ParsePubKey(pubKeyBytes)
+		if err != nil {
+			return nil, err
+		}
+		sig, err := ecdsa.ParseDERSignature(signature)
+		if err != nil {
+			return nil, fmt.Errorf("commit input %d: %v", i, err)
+		}
+		if !sig.Verify(sigHash, pubKey) {
+			return nil, fmt.Errorf("commit input %d: signature verify failed", i)
+		}
+		// P2SH-P2WPKH 的 scriptSig 已在构建时填入，签名只写 witness，不改变 commit txid
+		in.Witness = wire.TxWitness{append(signature, byte(txscript.SigHashAll)), pubKey.SerializeCompressed()}
+	}
+
+	offset := len(commitTx.TxIn)
+	for i := range builder.RevealTx {
+		sigHash, _ := hex.DecodeString(sigHashList[offset+i])
+		signature, err := DecodeHexString(signatures[offset+i])
+		if err != nil {
+			return nil, err
+		}
+		if err = verifySchnorrSignature(signature, sigHash, schnorr.SerializePubKey(builder.InscriptionTxCtxDataList[i].PublicKey)); err != nil {
+			return nil, fmt.Errorf("reveal tx %d: %v", i, err)
+		}
+		builder.setRevealTxWitness(i, signature)
+	}
+	if err = builder.checkRevealTxWeight(); err != nil {
+		return nil, err
+	}
+	return builder.inscribeTxs()
+}
+
+func newInscriptionToolForMPC(network *chaincfg.Params, request *InscriptionRequest, revealPublicKey string) (*InscriptionBuilder, error) {
+	revealPubKey, err := parseRevealPublicKey(revealPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	for i, prevOutput := range request.CommitTxPrevOutputList {
+		pkScript, err := AddrToPkScript(prevOutput.Address, network)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case txscript.IsPayToTaproot(pkScript):
+		case txscript.IsPayToWitnessPubKeyHash(pkScript), txscript.IsPayToScriptHash(pkScript):
+			if prevOutput.PublicKey == "" {
+				return nil, fmt.Errorf("commit input %d missing public key", i)
+			}
+		default:
+			// 非隔离见证输入签名后会改变 commit txid，导致预先计算的 reveal sighash 失效
+			return nil, fmt.Errorf("commit input %d: mpc inscription requires segwit or taproot input", i)
+		}
+	}
+
+	builder := newInscriptionBuilder(network, request)
+	if err = builder.buildUnsignedTxs(network, request, revealPubKey); err != nil {
+		return nil, err
+	}
+	// scriptSig 参与 txid 计算，P2SH-P2WPKH 输入须在关联 reveal 交易前填入 redeem script
+	for i, in := range builder.CommitTx.TxIn {
+		in.Witness = nil
+		in.SignatureScript = nil
+		prevOut := builder.CommitTxPrevOutputFetcher.FetchPrevOutput(in.PreviousOutPoint)
+		if !txscript.IsPayToScriptHash(prevOut.PkScript) {
+			continue
+		}
+		pubKeyBytes, err := DecodeHexString(builder.CommitTxPrevOutputList[i].PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		if in.SignatureScript, err = p2shWitnessPubKeyHashSigScript(pubKeyBytes); err != nil {
+			return nil, err
+		}
+	}
+	builder.linkRevealTx()
+	return builder, nil
+}
+
+// mpcSigHashList commit 交易每个输入的 sighash，之后是每笔 reveal 交易的 sighash
+func (builder *InscriptionBuilder) mpcSigHashList() ([]string, error) {
+	tx := builder.CommitTx
+	sigHashes := txscript.NewTxSigHashes(tx, builder.CommitTxPrevOutputFetcher)
+	sigHashList := make([]string, 0, len(tx.TxIn)+len(builder.RevealTx))
+	for i, in := range tx.TxIn {
+		prevOut := builder.CommitTxPrevOutputFetcher.FetchPrevOutput(in.PreviousOutPoint)
+		var sigHash []byte
+		var err error
+		if txscript.IsPayToTaproot(prevOut.PkScript) {
+			sigHash, err = txscript.CalcTaprootSignatureHash(sigHashes, txscript.SigHashDefault, tx, i, builder.CommitTxPrevOutputFetcher)
+		} else {
+			var pubKeyBytes []byte
+			pubKeyBytes, err = DecodeHexString(builder.CommitTxPrevOutputList[i].PublicKey)
+			if err != nil {
+				return nil, err
+			}
+			var script []byte
+			script, err = PayToPubKeyHashScript(btcutil.Hash160(pubKeyBytes))
+			if err != nil {
+				return nil, err
+			}
+			sigHash, err = txscript.CalcWitnessSigHash(script, sigHashes, txscript.SigHashAll, tx, i, prevOut.Value)
+		}
+		if err != nil {
+			return nil, err
+		}
+		sigHashList = append(sigHashList, hex.EncodeToString(sigHash))
+	}
+	for i := range builder.RevealTx {
+		sigHash, err := builder.revealTxSigHash(i)
+		if err != nil {
+			return nil, err
+		}
+		sigHashList = append(sigHashList, hex.EncodeToString(sigHash))
+	}
+	return sigHashList, nil
+}
+
+// fillEstimateWitness 按输入类型填充与真实签名等长的占位数据，用于没有私钥时估算 vsize
+func fillEstimateWitness(tx *wire.MsgTx, prevOutputs PrevOutputs, prevOutFetcher *txscript.MultiPrevOutFetcher) error {
+	for i, in := range tx.TxIn {
+		prevOut := prevOutFetcher.FetchPrevOutput(in.PreviousOutPoint)
+		if prevOut == nil {
+			return errors.New("prev output not found")
+		}
+		if txscript.IsPayToTaproot(prevOut.PkScript) {
+			in.Witness = wire.TxWitness{make([]byte, 64)}
+			continue
+		}
+		pubKey := make([]byte, 33)
+		if i < len(prevOutputs) && prevOutputs[i].PublicKey != "" {
+			b, err := DecodeHexString(prevOutputs[i].PublicKey)
+			if err != nil {
+				return err
+			}
+			pubKey = b
+		}
+		if txscript.IsPayToPubKeyHash(prevOut.PkScript) {
+			sigScript, err := txscript.NewScriptBuilder().AddData(make([]byte, 73)).AddData(pubKey).Script()
+			if err != nil {
+				return err
+			}
+			in.SignatureScript = sigScript
+			continue
+		}
+		in.Witness = wire.TxWitness{make([]byte, 73), pubKey}
+		if txscript.IsPayToScriptHash(prevOut.PkScript) {
+			sigScript, err := p2shWitnessPubKeyHashSigScript(pubKey)
+			if err != nil {
+				return err
+			}
+			in.SignatureScript = sigScript
+		}
+	}
+	return nil
+}
+
+// p2shWitnessPubKeyHashSigScript P2SH-P2WPKH 的 scriptSig：push 0014<hash160(pubKey)>
+func p2shWitnessPubKeyHashSigScript(pubKey []byte) ([]byte, error) {
+	redeemScript, err := PayToWitnessPubKeyHashScript(btcutil.Hash160(pubKey))
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{byte(len(redeemScript))}, redeemScript...), nil
+}
+
+func parseRevealPublicKey(publicKey string) (*btcec.PublicKey, error) {
+	b, err := DecodeHexString(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == schnorr.PubKeyBytesLen {
+		return schnorr.ParsePubKey(b)
+	}
+	return btcec.ParsePubKey(b)
+}
+
+func verifySchnorrSignature(signature, sigHash, xOnlyPubKey []byte) error {
+	sig, err := schnorr.ParseSignature(signature)
+	if err != nil {
+		return err
+	}
+	pubKey, err := schnorr.ParsePubKey(xOnlyPubKey)
+	if err != nil {
+		return err
+	}
+	if !sig.Verify(sigHash, pubKey) {
+		return errors.New("signature verify failed")
+	}
+	return nil
+}
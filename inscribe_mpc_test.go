@@ -0,0 +1,226 @@
+package txBuilder
+
+import (
+	"encoding/hex"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"strings"
+	"testing"
+)
+
+func mpcTestRequest(t *testing.T, segwitKey, taprootKey *btcec.PrivateKey) *InscriptionRequest {
+	net := &chaincfg.TestNet3Params
+	segwitAddr, err := btcutil.NewAddressWitnessPubKeyHash(btcutil.Hash160(segwitKey.PubKey().SerializeCompressed()), net)
+	if nil != err {
+		t.Fatal(err)
+	}
+	taprootAddr, err := btcutil.NewAddressTaproot(schnorr.SerializePubKey(txscript.ComputeTaprootKeyNoScript(taprootKey.PubKey())), net)
+	if nil != err {
+		t.Fatal(err)
+	}
+	return &InscriptionRequest{
+		CommitTxPrevOutputList: PrevOutputs{
+			{
+				TxId:      "453aa6dd39f31f06cd50b72a8683b8c0402ab36f889d96e4ef5ae14fe9b6fc32",
+				VOut:      0,
+				Amount:    100000,
+				Address:   segwitAddr.EncodeAddress(),
+				PublicKey: hex.EncodeToString(segwitKey.PubKey().SerializeCompressed()),
+			},
+			{
+				TxId:    "22c8a4db2b3b3a8df3d4c5e1d4a0d1f8a5d7e1f3b0c8b5e0d3f3c8a5b2e1d0c9",
+				VOut:    1,
+				Amount:  50000,
+				Address: taprootAddr.EncodeAddress(),
+			},
+		},
+		CommitFeeRate: 2,
+		RevealFeeRate: 2,
+		InscriptionDataList: []InscriptionData{
+			{
+				ContentType: "text/plain;charset=utf-8",
+				Body:        []byte(`{"p":"brc-20","op":"mint","tick":"xcvb","amt":"100"}`),
+				RevealAddr:  taprootAddr.EncodeAddress(),
+			},
+		},
+		ChangeAddress: segwitAddr.EncodeAddress(),
+	}
+}
+
+func TestInscribeForMPC(t *testing.T) {
+	segwitKey, _ := btcec.NewPrivateKey()
+	taprootKey, _ := btcec.NewPrivateKey()
+	revealKey, _ := btcec.NewPrivateKey()
+	net := &chaincfg.TestNet3Params
+	request := mpcTestRequest(t, segwitKey, taprootKey)
+	revealPublicKey := hex.EncodeToString(revealKey.PubKey().SerializeCompressed())
+
+	signatures := mpcSignAndVerify(t, net, request, segwitKey, taprootKey, revealKey)
+	signatures[2] = signatures[1]
+	if _, err := InscribeForMPCSigned(net, request, revealPublicKey, signatures); nil == err {
+		t.Fatal("expected reveal signature verify error")
+	}
+}
+
+func TestInscribeForMPCNestedSegwit(t *testing.T) {
+	segwitKey, _ := btcec.NewPrivateKey()
+	taprootKey, _ := btcec.NewPrivateKey()
+	revealKey, _ := btcec.NewPrivateKey()
+	net := &chaincfg.TestNet3Params
+	request := mpcTestRequest(t, segwitKey, taprootKey)
+	// P2SH-P2WPKH 输入的 scriptSig 会进入 commit txid
+	redeemScript, _ := PayToWitnessPubKeyHashScript(btcutil.Hash160(segwitKey.PubKey().SerializeCompressed()))
+	nestedAddr, err := btcutil.NewAddressScriptHash(redeemScript, net)
+	if nil != err {
+		t.Fatal(err)
+	}
+	request.CommitTxPrevOutputList[0].Address = nestedAddr.EncodeAddress()
+	mpcSignAndVerify(t, net, request, segwitKey, taprootKey, revealKey)
+}
+
+// mpcSignAndVerify 两阶段签名后执行脚本校验，并确认 reveal 花费的正是最终的 commit 交易
+func mpcSignAndVerify(t *testing.T, net *chaincfg.Params, request *InscriptionRequest, segwitKey, taprootKey, revealKey *btcec.PrivateKey) []string {
+	revealPublicKey := hex.EncodeToString(revealKey.PubKey().SerializeCompressed())
+	res, err := InscribeForMPCUnsigned(net, request, revealPublicKey)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if len(res.SigHashList) != 3 {
+		t.Fatalf("sigHashList len %d", len(res.SigHashList))
+	}
+
+	signatures := make([]string, len(res.SigHashList))
+	h0, _ := hex.DecodeString(res.SigHashList[0])
+	signatures[0] = hex.EncodeToString(ecdsa.Sign(segwitKey, h0).Serialize())
+	h1, _ := hex.DecodeString(res.SigHashList[1])
+	sig1, err := schnorr.Sign(txscript.TweakTaprootPrivKey(*taprootKey, nil), h1)
+	if nil != err {
+		t.Fatal(err)
+	}
+	signatures[1] = hex.EncodeToString(sig1.Serialize())
+	h2, _ := hex.DecodeString(res.SigHashList[2])
+	sig2, err := schnorr.Sign(revealKey, h2)
+	if nil != err {
+		t.Fatal(err)
+	}
+	signatures[2] = hex.EncodeToString(sig2.Serialize())
+
+	txs, err := InscribeForMPCSigned(net, request, revealPublicKey, signatures)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if txs.CommitTxFee != res.CommitTxFee {
+		t.Fatalf("commit fee changed %d %d", txs.CommitTxFee, res.CommitTxFee)
+	}
+
+	commitTx := decodeTestTx(t, txs.CommitTx)
+	fetcher := txscript.NewMultiPrevOutFetcher(nil)
+	for i, in := range commitTx.TxIn {
+		pkScript, _ := AddrToPkScript(request.CommitTxPrevOutputList[i].Address, net)
+		fetcher.AddPrevOut(in.PreviousOutPoint, wire.NewTxOut(request.CommitTxPrevOutputList[i].Amount, pkScript))
+	}
+	verifyTestTx(t, commitTx, fetcher)
+
+	revealTx := decodeTestTx(t, txs.RevealTxs[0])
+	if revealTx.TxIn[0].PreviousOutPoint.Hash != commitTx.TxHash() {
+		t.Fatalf("reveal spends %s, commit txid %s", revealTx.TxIn[0].PreviousOutPoint.Hash, commitTx.TxHash())
+	}
+	revealFetcher := txscript.NewMultiPrevOutFetcher(nil)
+	revealFetcher.AddPrevOut(revealTx.TxIn[0].PreviousOutPoint, commitTx.TxOut[0])
+	verifyTestTx(t, revealTx, revealFetcher)
+	return signatures
+}
+
+func TestInscribeForMPCRejectLegacyInput(t *testing.T) {
+	key, _ := btcec.NewPrivateKey()
+	net := &chaincfg.TestNet3Params
+	request := mpcTestRequest(t, key, key)
+	legacyAddr, _ := btcutil.NewAddressPubKeyHash(btcutil.Hash160(key.PubKey().SerializeCompressed()), net)
+	request.CommitTxPrevOutputList[0].Address = legacyAddr.EncodeAddress()
+	if _, err := InscribeForMPCUnsigned(net, request, hex.EncodeToString(key.PubKey().SerializeCompressed())); nil == err {
+		t.Fatal("expected legacy input error")
+	}
+}
+
+func decodeTestTx(t *testing.T, txHex string) *wire.MsgTx {
+	b, err := hex.DecodeString(txHex)
+	if nil != err {
+		t.Fatal(err)
+	}
+	tx := wire.NewMsgTx(DefaultTxVersion)
+	if err = tx.Deserialize(strings.NewReader(string(b))); nil != err {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func verifyTestTx(t *testing.T, tx *wire.MsgTx, fetcher *txscript.MultiPrevOutFetcher) {
+	sigHashes := txscript.NewTxSigHashes(tx, fetcher)
+	for i, in := range tx.TxIn {
+		prevOut := fetcher.FetchPrevOutput(in.PreviousOutPoint)
+		vm, err := txscript.NewEngine(prevOut.PkScript, tx, i, txscript.StandardVerifyFlags, nil, sigHashes, prevOut.Value, fetcher)
+		if nil != err {
+			t.Fatal(err)
+		}
+		if err = vm.Execute(); nil != err {
+			t.Fatalf("input %d: %v", i, err)
+		}
+	}
+}
+
+func TestRecoverCommitForMPC(t *testing.T) {
+	segwitKey, _ := btcec.NewPrivateKey()
+	taprootKey, _ := btcec.NewPrivateKey()
+	revealKey, _ := btcec.NewPrivateKey()
+	net := &chaincfg.TestNet3Params
+	request := mpcTestRequest(t, segwitKey, taprootKey)
+	revealPublicKey := hex.EncodeToString(revealKey.PubKey().SerializeCompressed())
+	signatures := mpcSignAndVerify(t, net, request, segwitKey, taprootKey, revealKey)
+	txs, err := InscribeForMPCSigned(net, request, revealPublicKey, signatures)
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	res, err := RecoverCommitForMPCUnsigned(net, txs.CommitTx, request, revealPublicKey, 2, request.ChangeAddress)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if len(res.SigHashList) != 1 || len(res.TapTweakList) != 1 {
+		t.Fatalf("sigHashList len %d", len(res.SigHashList))
+	}
+	// 按 BIP341 对私钥做 tweak：y 为奇数时取负再加 tweak
+	var tweak btcec.ModNScalar
+	tapTweak, _ := hex.DecodeString(res.TapTweakList[0])
+	tweak.SetByteSlice(tapTweak)
+	tweakedKey := *revealKey
+	if revealKey.PubKey().SerializeCompressed()[0] == 0x03 {
+		tweakedKey.Key.Negate()
+	}
+	tweakedKey.Key.Add(&tweak)
+	h, _ := hex.DecodeString(res.SigHashList[0])
+	sig, err := schnorr.Sign(&tweakedKey, h)
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	if _, _, err = RecoverCommitForMPCSigned(net, txs.CommitTx, request, revealPublicKey, 2, request.ChangeAddress, []string{signatures[2]}); nil == err {
+		t.Fatal("expected signature verify error")
+	}
+	txHex, _, err := RecoverCommitForMPCSigned(net, txs.CommitTx, request, revealPublicKey, 2, request.ChangeAddress, []string{hex.EncodeToString(sig.Serialize())})
+	if nil != err {
+		t.Fatal(err)
+	}
+	commitTx := decodeTestTx(t, txs.CommitTx)
+	recoverTx := decodeTestTx(t, txHex)
+	if commitTx.TxOut[0].Value-recoverTx.TxOut[0].Value != res.Fee {
+		t.Fatalf("recover fee %d", res.Fee)
+	}
+	fetcher := txscript.NewMultiPrevOutFetcher(nil)
+	fetcher.AddPrevOut(recoverTx.TxIn[0].PreviousOutPoint, commitTx.TxOut[0])
+	verifyTestTx(t, recoverTx, fetcher)
+}
@@ -0,0 +1,189 @@
+package txBuilder
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// RecoverCommit reveal 交易未广播时，将 commit 交易中的铭文输出转回 destination，不会铭刻。
+// request 需与构建 commit 交易时一致（用于重建铭文脚本和 taproot 承诺），
+// 私钥取 CommitTxPrevOutputList[0]，使用 tweak 后的内部私钥走 key path 花费，因此见证中不会出现铭文脚本。
+func RecoverCommit(network *chaincfg.Params, commitTxHex string, request *InscriptionRequest, feeRate int64, destination string) (txHex, txId string, err error) {
+	if len(request.CommitTxPrevOutputList) == 0 {
+		return "", "", errors.New("commit tx prev output miss")
+	}
+	privateKeyWif, err := btcutil.DecodeWIF(request.CommitTxPrevOutputList[0].PrivateKey)
+	if err != nil {
+		return "", "", err
+	}
+	privateKey := privateKeyWif.PrivKey
+	tx, prevOutFetcher, tapHashes, err := buildRecoverCommitTx(network, commitTxHex, request, privateKey.PubKey(), feeRate, destination)
+	if err != nil {
+		return "", "", err
+	}
+
+	sigHashes := txscript.NewTxSigHashes(tx, prevOutFetcher)
+	for i, in := range tx.TxIn {
+		prevOut := prevOutFetcher.FetchPrevOutput(in.PreviousOutPoint)
+		signature, err := txscript.RawTxInTaprootSignature(tx, sigHashes, i, prevOut.Value, prevOut.PkScript, tapHashes[i], txscript.SigHashDefault, privateKey)
+		if err != nil {
+			return "", "", err
+		}
+		in.Witness = wire.TxWitness{signature}
+	}
+
+	txHex, err = GetTxHex(tx)
+	if err != nil {
+		return "", "", err
+	}
+	return txHex, tx.TxHash().String(), nil
+}
+
+// RecoverCommitForMPCRes MPC 找回的待签名数据
+type RecoverCommitForMPCRes struct {
+	SigHashList []string `json:"sigHashList"`
+	// TapTweakList 每个输入的 taproot tweak，签名私钥为 reveal 私钥（公钥 y 为奇数时先取负）加上 tweak
+	TapTweakList []string `json:"tapTweakList"`
+	Tx           string   `json:"tx"`
+	Fee          int64    `json:"fee"`
+}
+
+// RecoverCommitForMPCUnsigned MPC 找回第一阶段：构建未签名的找回交易，返回每个输入的 BIP341 key path sighash。
+// revealPublicKey 与 InscribeForMPCUnsigned 时一致，签名须使用 tweak 后的私钥，见 TapTweakList。
+func RecoverCommitForMPCUnsigned(network *chaincfg.Params, commitTxHex string, request *InscriptionRequest, revealPublicKey string, feeRate int64, destination string) (*RecoverCommitForMPCRes, error) {
+	revealPubKey, err := parseRevealPublicKey(revealPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	tx, prevOutFetcher, tapHashes, err := buildRecoverCommitTx(network, commitTxHex, request, revealPubKey, feeRate, destination)
+	if err != nil {
+		return nil, err
+	}
+	res := &RecoverCommitForMPCRes{}
+	sigHashes := txscript.NewTxSigHashes(tx, prevOutFetcher)
+	totalAmount := int64(0)
+	for i, in := range tx.TxIn {
+		sigHash, err := txscript.CalcTaprootSignatureHash(sigHashes, txscript.SigHashDefault, tx, i, prevOutFetcher)
+		if err != nil {
+			return nil, err
+		}
+		tapTweak := chainhash.TaggedHash(chainhash.TagTapTweak, schnorr.SerializePubKey(revealPubKey), tapHashes[i])
+		res.SigHashList = append(res.SigHashList, hex.EncodeToString(sigHash))
+		res.TapTweakList = append(res.TapTweakList, hex.EncodeToString(tapTweak[:]))
+		totalAmount += prevOutFetcher.FetchPrevOutput(in.PreviousOutPoint).Value
+	}
+	res.Fee = totalAmount - tx.TxOut[0].Value
+	if res.Tx, err = GetTxHex(tx); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// RecoverCommitForMPCSigned MPC 找回第二阶段：使用与第一阶段相同的参数重建交易，按 SigHashList 的顺序填入 64 字节 schnorr 签名。
+func RecoverCommitForMPCSigned(network *chaincfg.Params, commitTxHex string, request *InscriptionRequest, revealPublicKey string, feeRate int64, destination string, signatures []string) (txHex, txId string, err error) {
+	revealPubKey, err := parseRevealPublicKey(revealPublicKey)
+	if err != nil {
+		return "", "", err
+	}
+	tx, prevOutFetcher, _, err := buildRecoverCommitTx(network, commitTxHex, request, revealPubKey, feeRate, destination)
+	if err != nil {
+		return "", "", err
+	}
+	if len(signatures) != len(tx.TxIn) {
+		return "", "", fmt.Errorf("signature miss, need %d got %d", len(tx.TxIn), len(signatures))
+	}
+	sigHashes := txscript.NewTxSigHashes(tx, prevOutFetcher)
+	for i, in := range tx.TxIn {
+		sigHash, err := txscript.CalcTaprootSignatureHash(sigHashes, txscript.SigHashDefault, tx, i, prevOutFetcher)
+		if err != nil {
+			return "", "", err
+		}
+		signature, err := DecodeHexString(signatures[i])
+		if err != nil {
+			return "", "", err
+		}
+		prevOut := prevOutFetcher.FetchPrevOutput(in.PreviousOutPoint)
+		if err = verifySchnorrSignature(signature, sigHash, prevOut.PkScript[2:]); err != nil {
+			return "", "", fmt.Errorf("recover input %d: %v", i, err)
+		}
+		in.Witness = wire.TxWitness{signature}
+	}
+
+	txHex, err = GetTxHex(tx)
+	if err != nil {
+		return "", "", err
+	}
+	return txHex, tx.TxHash().String(), nil
+}
+
+// buildRecoverCommitTx 构建未签名的找回交易，返回每个输入对应铭文脚本的 tap leaf hash
+func buildRecoverCommitTx(network *chaincfg.Params, commitTxHex string, request *InscriptionRequest, revealPubKey *btcec.PublicKey, feeRate int64, destination string) (*wire.MsgTx, *txscript.MultiPrevOutFetcher, [][]byte, error) {
+	commitTxBytes, err := hex.DecodeString(commitTxHex)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	commitTx := wire.NewMsgTx(DefaultTxVersion)
+	if err = commitTx.Deserialize(bytes.NewReader(commitTxBytes)); err != nil {
+		return nil, nil, nil, err
+	}
+	revealOutValue := DefaultRevealOutValue
+	if request.RevealOutValue > 0 {
+		revealOutValue = request.RevealOutValue
+	}
+	inscriptionTxCtxDataList, err := newInscriptionTxCtxDataList(network, request, revealPubKey, revealOutValue)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	tx := wire.NewMsgTx(DefaultTxVersion)
+	prevOutFetcher := txscript.NewMultiPrevOutFetcher(nil)
+	var tapHashes [][]byte
+	totalAmount := int64(0)
+	commitTxHash := commitTx.TxHash()
+	for i, out := range commitTx.TxOut {
+		for _, ctxData := range inscriptionTxCtxDataList {
+			if !bytes.Equal(out.PkScript, ctxData.CommitTxAddressPkScript) {
+				continue
+			}
+			outPoint := wire.NewOutPoint(&commitTxHash, uint32(i))
+			prevOutFetcher.AddPrevOut(*outPoint, out)
+			in := wire.NewTxIn(outPoint, nil, nil)
+			in.Sequence = DefaultSequenceNum
+			tx.AddTxIn(in)
+			tapHash := txscript.NewBaseTapLeaf(ctxData.InscriptionScript).TapHash()
+			tapHashes = append(tapHashes, tapHash[:])
+			totalAmount += out.Value
+			break
+		}
+	}
+	if len(tx.TxIn) == 0 {
+		return nil, nil, nil, errors.New("no inscription commit output found in commit tx")
+	}
+
+	pkScript, err := AddrToPkScript(destination, network)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	tx.AddTxOut(wire.NewTxOut(0, pkScript))
+	for _, in := range tx.TxIn {
+		in.Witness = wire.TxWitness{make([]byte, 64)}
+	}
+	fee := GetTxVirtualSize(btcutil.NewTx(tx)) * feeRate
+	if totalAmount-fee < DefaultMinChangeValue {
+		return nil, nil, nil, fmt.Errorf("insufficient balance, amount %d fee %d", totalAmount, fee)
+	}
+	tx.TxOut[0].Value = totalAmount - fee
+	for _, in := range tx.TxIn {
+		in.Witness = nil
+	}
+	return tx, prevOutFetcher, tapHashes, nil
+}
@@ -0,0 +1,153 @@
+package txBuilder
+
+import (
+	"bytes"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"testing"
+)
+
+func inscribeTestRequest(t *testing.T, key *btcec.PrivateKey, count int) *InscriptionRequest {
+	net := &chaincfg.TestNet3Params
+	wif, err := btcutil.NewWIF(key, net, true)
+	if nil != err {
+		t.Fatal(err)
+	}
+	addr, err := btcutil.NewAddressWitnessPubKeyHash(btcutil.Hash160(key.PubKey().SerializeCompressed()), net)
+	if nil != err {
+		t.Fatal(err)
+	}
+	request := &InscriptionRequest{
+		CommitTxPrevOutputList: PrevOutputs{
+			{
+				TxId:       "453aa6dd39f31f06cd50b72a8683b8c0402ab36f889d96e4ef5ae14fe9b6fc32",
+				VOut:       0,
+				Amount:     200000,
+				Address:    addr.EncodeAddress(),
+				PrivateKey: wif.String(),
+			},
+		},
+		CommitFeeRate: 3,
+		RevealFeeRate: 3,
+		ChangeAddress: addr.EncodeAddress(),
+	}
+	for i := 0; i < count; i++ {
+		request.InscriptionDataList = append(request.InscriptionDataList, InscriptionData{
+			ContentType: "text/plain;charset=utf-8",
+			Body:        []byte(`{"p":"brc-20","op":"mint","tick":"xcvb","amt":"100"}`),
+			RevealAddr:  addr.EncodeAddress(),
+		})
+	}
+	return request
+}
+
+func TestInscribeBatchReveal(t *testing.T) {
+	key, _ := btcec.NewPrivateKey()
+	request := inscribeTestRequest(t, key, 3)
+	request.BatchReveal = true
+
+	builder, err := NewInscriptionTool(&chaincfg.TestNet3Params, request)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if len(builder.RevealTx) != 1 || len(builder.RevealTx[0].TxOut) != 3 || len(builder.CommitTx.TxOut) != 2 {
+		t.Fatalf("unexpected tx shape reveal=%d outs=%d commitOuts=%d", len(builder.RevealTx), len(builder.RevealTx[0].TxOut), len(builder.CommitTx.TxOut))
+	}
+	// 第二、三个铭文分别指向 546、1092 sat
+	script := builder.InscriptionTxCtxDataList[0].InscriptionScript
+	if !bytes.Contains(script, []byte{txscript.OP_DATA_1, 2, txscript.OP_DATA_2, 0x22, 0x02}) ||
+		!bytes.Contains(script, []byte{txscript.OP_DATA_1, 2, txscript.OP_DATA_2, 0x44, 0x04}) {
+		t.Fatal("pointer tag miss")
+	}
+	verifyTestTx(t, builder.RevealTx[0], builder.RevealTxPrevOutputFetcher)
+	verifyTestTx(t, builder.CommitTx, builder.CommitTxPrevOutputFetcher)
+
+	commitTxFee, revealTxFees := builder.CalculateFee()
+	t.Log(commitTxFee, revealTxFees)
+}
+
+func TestCalculateInscribeFee(t *testing.T) {
+	key, _ := btcec.NewPrivateKey()
+	request := inscribeTestRequest(t, key, 5)
+
+	fee, err := CalculateInscribeFee(&chaincfg.TestNet3Params, request)
+	if nil != err {
+		t.Fatal(err)
+	}
+	t.Log(fee.PerItem.TotalFee, fee.PerItem.RevealTxFees)
+	t.Log(fee.Batch.TotalFee, fee.Batch.RevealTxFees)
+	if len(fee.PerItem.RevealTxFees) != 5 || len(fee.Batch.RevealTxFees) != 1 {
+		t.Fatal("unexpected reveal tx count")
+	}
+	if fee.Batch.TotalFee >= fee.PerItem.TotalFee {
+		t.Fatalf("batch fee %d not less than per item fee %d", fee.Batch.TotalFee, fee.PerItem.TotalFee)
+	}
+
+	// 已签名的 builder 直接比较，当前模式的手续费与 CalculateFee 一致
+	for _, batchReveal := range []bool{false, true} {
+		request.BatchReveal = batchReveal
+		builder, err := NewInscriptionTool(&chaincfg.TestNet3Params, request)
+		if nil != err {
+			t.Fatal(err)
+		}
+		comparison, err := builder.CalculateFeeComparison()
+		if nil != err {
+			t.Fatal(err)
+		}
+		if comparison.PerItem.TotalFee != fee.PerItem.TotalFee || comparison.Batch.TotalFee != fee.Batch.TotalFee {
+			t.Fatalf("comparison %+v %+v", comparison.PerItem, comparison.Batch)
+		}
+		commitTxFee, _ := builder.CalculateFee()
+		current := comparison.PerItem
+		if batchReveal {
+			current = comparison.Batch
+		}
+		if current.CommitTxFee != commitTxFee {
+			t.Fatalf("current mode commit fee %d %d", current.CommitTxFee, commitTxFee)
+		}
+	}
+	request.BatchReveal = false
+
+	txs, err := Inscribe(&chaincfg.TestNet3Params, request)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if len(txs.RevealTxs) != 5 {
+		t.Fatal("unexpected reveal tx count")
+	}
+}
+
+func TestRecoverCommit(t *testing.T) {
+	key, _ := btcec.NewPrivateKey()
+	net := &chaincfg.TestNet3Params
+	for _, batchReveal := range []bool{false, true} {
+		request := inscribeTestRequest(t, key, 2)
+		request.BatchReveal = batchReveal
+		txs, err := Inscribe(net, request)
+		if nil != err {
+			t.Fatal(err)
+		}
+
+		txHex, txId, err := RecoverCommit(net, txs.CommitTx, request, 2, request.ChangeAddress)
+		if nil != err {
+			t.Fatal(err)
+		}
+		t.Log(txId, txHex)
+
+		commitTx := decodeTestTx(t, txs.CommitTx)
+		recoverTx := decodeTestTx(t, txHex)
+		fetcher := txscript.NewMultiPrevOutFetcher(nil)
+		for _, in := range recoverTx.TxIn {
+			fetcher.AddPrevOut(in.PreviousOutPoint, commitTx.TxOut[in.PreviousOutPoint.Index])
+			if len(in.Witness) != 1 {
+				t.Fatal("recover must spend by key path")
+			}
+		}
+		if len(recoverTx.TxIn) != len(txs.RevealTxs) {
+			t.Fatalf("recover inputs %d", len(recoverTx.TxIn))
+		}
+		verifyTestTx(t, recoverTx, fetcher)
+	}
+}
@@ -0,0 +1,127 @@
+package txBuilder
+
+import (
+	"errors"
+	"fmt"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/wire"
+)
+
+type InscriptionTransferRequest struct {
+	// InscriptionUtxo 铭文所在 UTXO，Assets 为空时视为偏移 0 处有一个铭文
+	InscriptionUtxo *PrevOutput `json:"inscriptionUtxo"`
+	// CardinalUtxos 支付手续费的普通 UTXO，按顺序选取，携带资产的会被跳过
+	CardinalUtxos   PrevOutputs `json:"cardinalUtxos"`
+	ReceiverAddress string      `json:"receiverAddress"`
+	// Postage 接收方输出金额，为 0 时保留铭文 UTXO 原有金额，常见 546 或 10000
+	Postage        int64  `json:"postage"`
+	FeeRate        int64  `json:"feeRate"`
+	ChangeAddress  string `json:"changeAddress"`
+	MinChangeValue int64  `json:"minChangeValue"`
+}
+
+type InscriptionTransferTx struct {
+	TxHex        string   `json:"txHex"`
+	TxId         string   `json:"txId"`
+	Fee          int64    `json:"fee"`
+	Postage      int64    `json:"postage"`
+	ChangeAmount int64    `json:"changeAmount"`
+	CardinalUsed []string `json:"cardinalUsed"`
+}
+
+// TransferInscription 转移铭文：铭文 UTXO 作为第一个输入，使铭文 sat 落在输出 0（接收方，金额为 postage），
+// 手续费由普通 UTXO 支付，剩余找零。支持 P2TR/P2WPKH/P2SH-P2WPKH/P2PKH 输入
+func TransferInscription(network *chaincfg.Params, request *InscriptionTransferRequest) (*InscriptionTransferTx, error) {
+	inscriptionUtxo := request.InscriptionUtxo
+	if inscriptionUtxo == nil {
+		return nil, errors.New("inscription utxo miss")
+	}
+	assets := inscriptionUtxo.Assets
+	if !assets.HasAsset() {
+		assets = &UtxoAssets{Inscriptions: []*UtxoInscription{{Offset: 0}}}
+	}
+	postage := request.Postage
+	if postage <= 0 {
+		postage = inscriptionUtxo.Amount
+	}
+	if postage < DefaultRevealOutValue {
+		return nil, fmt.Errorf("postage %d less than dust %d", postage, DefaultRevealOutValue)
+	}
+	for _, inscription := range assets.Inscriptions {
+		if inscription.Offset >= postage {
+			return nil, fmt.Errorf("inscription %s offset %d not covered by postage %d", inscription.InscriptionId, inscription.Offset, postage)
+		}
+	}
+	minChangeValue := DefaultMinChangeValue
+	if request.MinChangeValue > 0 {
+		minChangeValue = request.MinChangeValue
+	}
+
+	build := func(cardinalUtxos PrevOutputs, changeAmount int64) (*wire.MsgTx, error) {
+		txBuild := NewTxBuild(DefaultTxVersion, network)
+		txBuild.AllowAssetInputs()
+		txBuild.AddInputWithAssets(inscriptionUtxo.TxId, inscriptionUtxo.VOut, inscriptionUtxo.PrivateKey, inscriptionUtxo.Address, inscriptionUtxo.Amount, assets)
+		for _, v := range cardinalUtxos {
+			txBuild.AddInput2(v.TxId, v.VOut, v.PrivateKey, v.Address, v.Amount)
+		}
+		txBuild.AddOutput(request.ReceiverAddress, postage)
+		if changeAmount > 0 {
+			txBuild.AddOutput(request.ChangeAddress, changeAmount)
+		}
+		return txBuild.Build()
+	}
+
+	cardinalUtxos := make(PrevOutputs, 0)
+	totalInput := inscriptionUtxo.Amount
+	for _, candidate := range append(PrevOutputs{nil}, request.CardinalUtxos.Cardinal()...) {
+		if candidate != nil {
+			cardinalUtxos = append(cardinalUtxos, candidate)
+			totalInput += candidate.Amount
+		}
+		if totalInput <= postage {
+			continue
+		}
+		// 先按有找零估算
+		tx, err := build(cardinalUtxos, totalInput-postage)
+		if err != nil {
+			return nil, err
+		}
+		fee := GetTxVirtualSize(btcutil.NewTx(tx)) * request.FeeRate
+		changeAmount := totalInput - postage - fee
+		if changeAmount < minChangeValue {
+			// 去掉找零输出后重新估算，多余部分作为手续费
+			tx, err = build(cardinalUtxos, 0)
+			if err != nil {
+				return nil, err
+			}
+			fee = GetTxVirtualSize(btcutil.NewTx(tx)) * request.FeeRate
+			if totalInput-postage < fee {
+				continue
+			}
+			changeAmount = 0
+		} else {
+			tx, err = build(cardinalUtxos, changeAmount)
+			if err != nil {
+				return nil, err
+			}
+		}
+		txHex, err := GetTxHex(tx)
+		if err != nil {
+			return nil, err
+		}
+		cardinalUsed := make([]string, len(cardinalUtxos))
+		for i, v := range cardinalUtxos {
+			cardinalUsed[i] = fmt.Sprintf("%s:%d", v.TxId, v.VOut)
+		}
+		return &InscriptionTransferTx{
+			TxHex:        txHex,
+			TxId:         tx.TxHash().String(),
+			Fee:          totalInput - postage - changeAmount,
+			Postage:      postage,
+			ChangeAmount: changeAmount,
+			CardinalUsed: cardinalUsed,
+		}, nil
+	}
+	return nil, errors.New("insufficient balance")
+}
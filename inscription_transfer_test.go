@@ -0,0 +1,91 @@
+package txBuilder
+
+import (
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"testing"
+)
+
+func TestTransferInscription(t *testing.T) {
+	net := &chaincfg.TestNet3Params
+	key, _ := btcec.NewPrivateKey()
+	wif, _ := btcutil.NewWIF(key, net, true)
+	taprootAddr, _ := btcutil.NewAddressTaproot(schnorr.SerializePubKey(txscript.ComputeTaprootKeyNoScript(key.PubKey())), net)
+	segwitAddr, _ := btcutil.NewAddressWitnessPubKeyHash(btcutil.Hash160(key.PubKey().SerializeCompressed()), net)
+	receiver, _ := btcec.NewPrivateKey()
+	receiverAddr, _ := btcutil.NewAddressTaproot(schnorr.SerializePubKey(txscript.ComputeTaprootKeyNoScript(receiver.PubKey())), net)
+
+	request := &InscriptionTransferRequest{
+		InscriptionUtxo: &PrevOutput{
+			TxId:       "453aa6dd39f31f06cd50b72a8683b8c0402ab36f889d96e4ef5ae14fe9b6fc32",
+			VOut:       0,
+			Amount:     10000,
+			Address:    taprootAddr.EncodeAddress(),
+			PrivateKey: wif.String(),
+			Assets: &UtxoAssets{Inscriptions: []*UtxoInscription{
+				{InscriptionId: "453aa6dd39f31f06cd50b72a8683b8c0402ab36f889d96e4ef5ae14fe9b6fc32i0", Offset: 0},
+			}},
+		},
+		CardinalUtxos: PrevOutputs{
+			{
+				TxId:       "22c8a4db2b3b3a8df3d4c5e1d4a0d1f8a5d7e1f3b0c8b5e0d3f3c8a5b2e1d0c9",
+				VOut:       1,
+				Amount:     3000,
+				Address:    segwitAddr.EncodeAddress(),
+				PrivateKey: wif.String(),
+				Assets:     &UtxoAssets{Runes: []*UtxoRune{{RuneId: "840000:3", Amount: "1"}}},
+			},
+			{
+				TxId:       "22c8a4db2b3b3a8df3d4c5e1d4a0d1f8a5d7e1f3b0c8b5e0d3f3c8a5b2e1d0c9",
+				VOut:       2,
+				Amount:     5000,
+				Address:    segwitAddr.EncodeAddress(),
+				PrivateKey: wif.String(),
+			},
+		},
+		ReceiverAddress: receiverAddr.EncodeAddress(),
+		Postage:         10000,
+		FeeRate:         10,
+		ChangeAddress:   segwitAddr.EncodeAddress(),
+	}
+
+	res, err := TransferInscription(net, request)
+	if nil != err {
+		t.Fatal(err)
+	}
+	t.Log(res.TxId, res.Fee, res.ChangeAmount)
+
+	tx := decodeTestTx(t, res.TxHex)
+	if len(tx.TxIn) != 2 || tx.TxIn[1].PreviousOutPoint.Index != 2 {
+		t.Fatal("rune utxo must not be used for fee")
+	}
+	if tx.TxOut[0].Value != 10000 || res.Fee+res.ChangeAmount != 5000 {
+		t.Fatalf("unexpected amounts %d %d %d", tx.TxOut[0].Value, res.Fee, res.ChangeAmount)
+	}
+	fetcher := txscript.NewMultiPrevOutFetcher(nil)
+	for i, v := range []*PrevOutput{request.InscriptionUtxo, request.CardinalUtxos[1]} {
+		pkScript, _ := AddrToPkScript(v.Address, net)
+		fetcher.AddPrevOut(tx.TxIn[i].PreviousOutPoint, wire.NewTxOut(v.Amount, pkScript))
+	}
+	verifyTestTx(t, tx, fetcher)
+
+	// postage 小于 UTXO 金额时，多出的 sat 进入找零，无需额外普通 UTXO
+	request.Postage = 546
+	request.CardinalUtxos = nil
+	res, err = TransferInscription(net, request)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if len(res.CardinalUsed) != 0 || res.ChangeAmount == 0 {
+		t.Fatalf("unexpected result %+v", res)
+	}
+
+	request.InscriptionUtxo.Assets.Inscriptions[0].Offset = 600
+	if _, err = TransferInscription(net, request); nil == err {
+		t.Fatal("expected offset error")
+	}
+}
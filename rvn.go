@@ -22,6 +22,9 @@ func RVNSignTxLegacyCompressedPKStr(txBuild *TransactionBuilder, privateKeyStr s
 
 // RVNSignTxLegacyCompressed 基于压缩的公钥地址，签名交易获得 hex
 func RVNSignTxLegacyCompressed(txBuild *TransactionBuilder, privateBytes []byte) (txHex, txId string, err error) {
+	if err = txBuild.checkAssetInputs(); nil != err {
+		return "", "", err
+	}
 	privateKey, _ := btcec.PrivKeyFromBytes(btcec.S256(), privateBytes)
 
 	// 构造交易
@@ -0,0 +1,139 @@
+package sol
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/compute_budget"
+	"github.com/blocto/solana-go-sdk/types"
+	"math"
+	"math/bits"
+)
+
+const (
+	// BaseFeePerSignature 每个签名的基础手续费（lamports）
+	BaseFeePerSignature = uint64(5000)
+	// DefaultInstructionComputeUnitLimit 未设置 SetComputeUnitLimit 时每条非内置程序指令的默认计算单元
+	DefaultInstructionComputeUnitLimit = uint32(200_000)
+	// BuiltinInstructionComputeUnitLimit SIMD-0170 起内置程序（System、ComputeBudget 等）指令的默认计算单元
+	BuiltinInstructionComputeUnitLimit = uint32(3_000)
+	// MaxComputeUnitLimit 单笔交易计算单元上限
+	MaxComputeUnitLimit = uint32(1_400_000)
+
+	microLamportsPerLamport = 1_000_000
+
+	// ComputeBudget 指令序号
+	instructionSetComputeUnitLimit = 2
+	instructionSetComputeUnitPrice = 3
+)
+
+// builtinProgramIDs 按 3000 计算单元计的内置程序，Stake、Config、AddressLookupTable 已迁移为 BPF 程序，按 200000 计
+var builtinProgramIDs = map[common.PublicKey]struct{}{
+	common.SystemProgramID:               {},
+	common.ComputeBudgetProgramID:        {},
+	common.VoteProgramID:                 {},
+	common.BPFLoaderProgramID:            {},
+	common.BPFLoaderUpgradeableProgramID: {},
+	common.Secp256k1ProgramID:            {},
+	common.PublicKeyFromString("BPFLoader2111111111111111111111111111111111"): {},
+	common.PublicKeyFromString("LoaderV411111111111111111111111111111111111"): {},
+	common.PublicKeyFromString("Ed25519SigVerify111111111111111111111111111"): {},
+}
+
+// Fee 交易手续费（lamports）
+type Fee struct {
+	Signatures       int    `json:"signatures"`
+	ComputeUnitLimit uint32 `json:"computeUnitLimit"`
+	// ComputeUnitPrice 每计算单元的价格（micro-lamports，1e-6 lamport）
+	ComputeUnitPrice uint64 `json:"computeUnitPrice"`
+	BaseFee          uint64 `json:"baseFee"`
+	PriorityFee      uint64 `json:"priorityFee"`
+	Total            uint64 `json:"total"`
+}
+
+// WithComputeBudget 在指令前插入 SetComputeUnitLimit、SetComputeUnitPrice，参数为 0 时不插入对应指令。
+// computeUnitLimit 建议取 simulateTransaction 的 unitsConsumed 上浮 10%~20%，按 limit 而非实际消耗收取优先费
+func WithComputeBudget(instructions []types.Instruction, computeUnitLimit uint32, microLamports uint64) ([]types.Instruction, error) {
+	if computeUnitLimit > MaxComputeUnitLimit {
+		return nil, fmt.Errorf("compute unit limit %d more than %d", computeUnitLimit, MaxComputeUnitLimit)
+	}
+	for _, instruction := range instructions {
+		if instruction.ProgramID == common.ComputeBudgetProgramID {
+			return nil, errors.New("instructions already contain compute budget")
+		}
+	}
+	budget := make([]types.Instruction, 0, 2+len(instructions))
+	if computeUnitLimit > 0 {
+		budget = append(budget, compute_budget.SetComputeUnitLimit(compute_budget.SetComputeUnitLimitParam{Units: computeUnitLimit}))
+	}
+	if microLamports > 0 {
+		budget = append(budget, compute_budget.SetComputeUnitPrice(compute_budget.SetComputeUnitPriceParam{MicroLamports: microLamports}))
+	}
+	return append(budget, instructions...), nil
+}
+
+// PriorityFee 优先费：ceil(computeUnitLimit * microLamports / 1e6)，溢出时与链上一致取 u64 最大值
+func PriorityFee(computeUnitLimit uint32, microLamports uint64) uint64 {
+	hi, lo := bits.Mul64(uint64(computeUnitLimit), microLamports)
+	if hi >= microLamportsPerLamport {
+		return math.MaxUint64
+	}
+	fee, rem := bits.Div64(hi, lo, microLamportsPerLamport)
+	if rem > 0 {
+		fee++
+	}
+	return fee
+}
+
+// CalculateFee 离线计算手续费：签名数 * 5000 + 优先费
+func CalculateFee(signatures int, computeUnitLimit uint32, microLamports uint64) *Fee {
+	fee := &Fee{
+		Signatures:       signatures,
+		ComputeUnitLimit: computeUnitLimit,
+		ComputeUnitPrice: microLamports,
+		BaseFee:          uint64(signatures) * BaseFeePerSignature,
+		PriorityFee:      PriorityFee(computeUnitLimit, microLamports),
+	}
+	if fee.Total = fee.BaseFee + fee.PriorityFee; fee.Total < fee.PriorityFee {
+		fee.Total = math.MaxUint64
+	}
+	return fee
+}
+
+// MessageFee 按消息中的签名数与 ComputeBudget 指令计算手续费，
+// 未设置 limit 时与链上默认值一致：内置程序指令（含 ComputeBudget 指令）按 3000、其他指令按 200000 计算单元
+func MessageFee(message types.Message) (*Fee, error) {
+	var limit, builtinCount, instructionCount uint32
+	var price uint64
+	limitSet := false
+	for _, instruction := range message.DecompileInstructions() {
+		if _, ok := builtinProgramIDs[instruction.ProgramID]; ok {
+			builtinCount++
+		} else {
+			instructionCount++
+		}
+		if instruction.ProgramID != common.ComputeBudgetProgramID {
+			continue
+		}
+		if len(instruction.Data) == 0 {
+			return nil, errors.New("invalid compute budget instruction")
+		}
+		switch instruction.Data[0] {
+		case instructionSetComputeUnitLimit:
+			if len(instruction.Data) != 5 {
+				return nil, errors.New("invalid set compute unit limit instruction")
+			}
+			limit, limitSet = binary.LittleEndian.Uint32(instruction.Data[1:]), true
+		case instructionSetComputeUnitPrice:
+			if len(instruction.Data) != 9 {
+				return nil, errors.New("invalid set compute unit price instruction")
+			}
+			price = binary.LittleEndian.Uint64(instruction.Data[1:])
+		}
+	}
+	if !limitSet {
+		limit = min(builtinCount*BuiltinInstructionComputeUnitLimit+instructionCount*DefaultInstructionComputeUnitLimit, MaxComputeUnitLimit)
+	}
+	return CalculateFee(int(message.Header.NumRequireSignatures), min(limit, MaxComputeUnitLimit), price), nil
+}
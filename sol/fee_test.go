@@ -0,0 +1,89 @@
+package sol
+
+import (
+	"encoding/binary"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/system"
+	"github.com/blocto/solana-go-sdk/types"
+	"testing"
+)
+
+func TestPriorityFee(t *testing.T) {
+	if fee := PriorityFee(200_000, 1); fee != 1 {
+		t.Fatalf("fee %d", fee)
+	}
+	if fee := PriorityFee(300_000, 50_000); fee != 15_000 {
+		t.Fatalf("fee %d", fee)
+	}
+	if fee := PriorityFee(MaxComputeUnitLimit, ^uint64(0)); fee != ^uint64(0) {
+		t.Fatal("fee overflow")
+	}
+	fee := CalculateFee(2, 300_000, 50_000)
+	if fee.BaseFee != 10_000 || fee.Total != 25_000 {
+		t.Fatalf("fee %+v", fee)
+	}
+}
+
+func TestTransferWithComputeBudget(t *testing.T) {
+	opt := &TxOption{ComputeUnitLimit: 1_000, ComputeUnitPrice: 2_000_000, Memo: "uid-1"}
+	signed, err := TransferSOL(testPrivateKey, testTo, 1_000_000, testBlockhash, opt)
+	if nil != err {
+		t.Fatal(err)
+	}
+	tx, _ := DecodeTx(signed.Base64)
+	instructions := tx.Message.DecompileInstructions()
+	if len(instructions) != 4 || instructions[0].ProgramID != common.ComputeBudgetProgramID || instructions[1].ProgramID != common.ComputeBudgetProgramID ||
+		instructions[2].ProgramID != common.SystemProgramID || instructions[3].ProgramID != common.MemoProgramID {
+		t.Fatalf("instructions %+v", instructions)
+	}
+	if instructions[0].Data[0] != 2 || binary.LittleEndian.Uint32(instructions[0].Data[1:]) != 1_000 ||
+		instructions[1].Data[0] != 3 || binary.LittleEndian.Uint64(instructions[1].Data[1:]) != 2_000_000 {
+		t.Fatalf("compute budget %x %x", instructions[0].Data, instructions[1].Data)
+	}
+	fee, err := MessageFee(tx.Message)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if fee.Signatures != 1 || fee.ComputeUnitLimit != 1_000 || fee.PriorityFee != 2_000 || fee.Total != 7_000 {
+		t.Fatalf("fee %+v", fee)
+	}
+
+	// 只设置单价时按指令估算 limit：ComputeBudget、system 各 3000，memo 200000
+	message, _ := NewTransferMessage(testFrom, testTo, 1, testBlockhash, &TxOption{ComputeUnitPrice: 10, Memo: "uid-1"})
+	fee, _ = MessageFee(message)
+	if fee.ComputeUnitLimit != 206_000 || fee.PriorityFee != 3 {
+		t.Fatalf("fee %+v", fee)
+	}
+
+	message, _ = NewTransferMessage(testFrom, testTo, 1, testBlockhash, nil)
+	if fee, _ = MessageFee(message); fee.ComputeUnitLimit != BuiltinInstructionComputeUnitLimit || fee.Total != BaseFeePerSignature {
+		t.Fatalf("fee %+v", fee)
+	}
+
+	if _, err := NewTransferMessage(testFrom, testTo, 1, testBlockhash, &TxOption{ComputeUnitLimit: MaxComputeUnitLimit + 1}); nil == err {
+		t.Fatal("expected compute unit limit error")
+	}
+}
+
+func TestNewMessageKeepsCallerComputeBudget(t *testing.T) {
+	from := common.PublicKeyFromString(testFrom)
+	instructions, err := WithComputeBudget([]types.Instruction{system.Transfer(system.TransferParam{
+		From:   from,
+		To:     common.PublicKeyFromString(testTo),
+		Amount: 1,
+	})}, 300, 0)
+	if nil != err {
+		t.Fatal(err)
+	}
+	// 只设置 Memo 时不再插入 ComputeBudget，不应报重复
+	message, err := NewMessage(testFrom, testBlockhash, instructions, &TxOption{Memo: "uid-1"})
+	if nil != err {
+		t.Fatal(err)
+	}
+	if fee, _ := MessageFee(message); fee.ComputeUnitLimit != 300 {
+		t.Fatalf("fee %+v", fee)
+	}
+	if _, err := NewMessage(testFrom, testBlockhash, instructions, &TxOption{ComputeUnitPrice: 1}); nil == err {
+		t.Fatal("expected duplicate compute budget error")
+	}
+}
@@ -0,0 +1,190 @@
+package sol
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/associated_token_account"
+	"github.com/blocto/solana-go-sdk/program/token"
+	"github.com/blocto/solana-go-sdk/types"
+	"math/bits"
+)
+
+// 代币程序，Token-2022 的代币账户与 ATA 均由 Token-2022 程序管理
+var (
+	TokenProgramID     = common.TokenProgramID
+	Token2022ProgramID = common.Token2022ProgramID
+)
+
+const (
+	// token-2022 TokenInstruction::TransferFeeExtension 与其子指令 TransferCheckedWithFee
+	instructionTransferFeeExtension   = 26
+	instructionTransferCheckedWithFee = 1
+	// maxFeeBasisPoints 万分比
+	maxFeeBasisPoints = 10_000
+)
+
+// FindAssociatedTokenAddressWithProgram 按代币程序计算 ATA，FindAssociatedTokenAddress 只适用于 Token 程序
+func FindAssociatedTokenAddressWithProgram(walletAddress, tokenMintAddress, tokenProgramId common.PublicKey) (common.PublicKey, uint8, error) {
+	return common.FindProgramAddress([][]byte{
+		walletAddress.Bytes(),
+		tokenProgramId.Bytes(),
+		tokenMintAddress.Bytes(),
+	}, common.SPLAssociatedTokenAccountProgramID)
+}
+
+// TransferFee Token-2022 transfer-fee 扩展当前 epoch 的费率，取自 mint 账户的 TransferFeeConfig
+type TransferFee struct {
+	BasisPoints uint16 `json:"transferFeeBasisPoints"`
+	MaximumFee  uint64 `json:"maximumFee"`
+}
+
+// Fee 与链上计算一致：ceil(amount * basisPoints / 10000)，不超过 MaximumFee。手续费从转账数量中扣除，收款方到账 amount - fee
+func (f *TransferFee) Fee(amount uint64) uint64 {
+	if f == nil || f.BasisPoints == 0 || amount == 0 {
+		return 0
+	}
+	hi, lo := bits.Mul64(amount, uint64(f.BasisPoints))
+	// amount 为 u64、basisPoints 不超过 10000，商不会溢出 u64
+	fee, rem := bits.Div64(hi, lo, maxFeeBasisPoints)
+	if rem > 0 {
+		fee++
+	}
+	return min(fee, f.MaximumFee)
+}
+
+// SPLTransfer SPL Token 转账参数，代币账户由钱包地址推导 ATA
+type SPLTransfer struct {
+	// Owner 转出钱包地址，同时支付手续费与创建 ATA 的租金
+	Owner string `json:"owner"`
+	// To 收款钱包地址（非代币账户）
+	To   string `json:"to"`
+	Mint string `json:"mint"`
+	// Amount 代币最小单位
+	Amount   uint64 `json:"amount"`
+	Decimals uint8  `json:"decimals"`
+	// TokenProgram 为空时为 Token 程序，Token-2022 代币须填 Token2022ProgramID
+	TokenProgram string `json:"tokenProgram"`
+	// CreateAta 收款 ATA 不存在时创建，使用 CreateIdempotent，ATA 已存在时不会失败
+	CreateAta bool `json:"createAta"`
+	// TransferFee Token-2022 mint 启用 transfer-fee 扩展时必填，使用 TransferCheckedWithFee
+	TransferFee *TransferFee `json:"transferFee"`
+}
+
+// Instructions 生成转账指令：可选的 CreateIdempotent + TransferChecked(WithFee)
+func (p *SPLTransfer) Instructions() ([]types.Instruction, error) {
+	if p.Amount == 0 {
+		return nil, errors.New("amount must be positive")
+	}
+	keys := make([]common.PublicKey, 3)
+	for i, address := range []string{p.Owner, p.To, p.Mint} {
+		key, err := publicKey(address)
+		if nil != err {
+			return nil, err
+		}
+		keys[i] = key
+	}
+	owner, to, mint := keys[0], keys[1], keys[2]
+	programId := TokenProgramID
+	if p.TokenProgram != "" {
+		var err error
+		if programId, err = publicKey(p.TokenProgram); nil != err {
+			return nil, err
+		}
+		if programId != TokenProgramID && programId != Token2022ProgramID {
+			return nil, fmt.Errorf("unsupported token program: %s", p.TokenProgram)
+		}
+	}
+	if p.TransferFee != nil {
+		if programId != Token2022ProgramID {
+			return nil, errors.New("transfer fee only supported by token-2022")
+		}
+		if p.TransferFee.BasisPoints > maxFeeBasisPoints {
+			return nil, fmt.Errorf("transfer fee basis points %d more than %d", p.TransferFee.BasisPoints, maxFeeBasisPoints)
+		}
+	}
+
+	fromAta, _, err := FindAssociatedTokenAddressWithProgram(owner, mint, programId)
+	if nil != err {
+		return nil, err
+	}
+	toAta, _, err := FindAssociatedTokenAddressWithProgram(to, mint, programId)
+	if nil != err {
+		return nil, err
+	}
+	if fromAta == toAta {
+		return nil, errors.New("cannot transfer token to yourself")
+	}
+
+	instructions := make([]types.Instruction, 0, 2)
+	if p.CreateAta {
+		create := associated_token_account.CreateIdempotent(associated_token_account.CreateIdempotentParam{
+			Funder:                 owner,
+			Owner:                  to,
+			Mint:                   mint,
+			AssociatedTokenAccount: toAta,
+		})
+		// sdk 固定为 Token 程序，替换账户列表中的代币程序
+		for i := range create.Accounts {
+			if create.Accounts[i].PubKey == TokenProgramID {
+				create.Accounts[i].PubKey = programId
+			}
+		}
+		instructions = append(instructions, create)
+	}
+
+	transfer := token.TransferChecked(token.TransferCheckedParam{
+		From:     fromAta,
+		To:       toAta,
+		Mint:     mint,
+		Auth:     owner,
+		Amount:   p.Amount,
+		Decimals: p.Decimals,
+	})
+	transfer.ProgramID = programId
+	if p.TransferFee != nil {
+		// 账户顺序与 TransferChecked 相同，数据追加预期手续费，与链上计算不一致时交易失败
+		data := make([]byte, 0, 19)
+		data = append(data, instructionTransferFeeExtension, instructionTransferCheckedWithFee)
+		data = binary.LittleEndian.AppendUint64(data, p.Amount)
+		data = append(data, p.Decimals)
+		transfer.Data = binary.LittleEndian.AppendUint64(data, p.TransferFee.Fee(p.Amount))
+	}
+	return append(instructions, transfer), nil
+}
+
+// NewSPLTransferMessage 构建 SPL Token 转账消息，手续费由 Owner 支付
+func NewSPLTransferMessage(param *SPLTransfer, recentBlockhash string, opt *TxOption) (types.Message, error) {
+	if param == nil {
+		return types.Message{}, errors.New("spl transfer param miss")
+	}
+	instructions, err := param.Instructions()
+	if nil != err {
+		return types.Message{}, err
+	}
+	return NewMessage(param.Owner, recentBlockhash, instructions, opt)
+}
+
+// TransferSPL 构建并签名 SPL Token 转账，param.Owner 为空时取私钥对应地址
+func TransferSPL(privateHex string, param *SPLTransfer, recentBlockhash string, opt *TxOption) (*SignedTx, error) {
+	if param == nil {
+		return nil, errors.New("spl transfer param miss")
+	}
+	pk, err := ImportPrivateKeyFromHex(privateHex)
+	if nil != err {
+		return nil, err
+	}
+	p := *param
+	owner, _ := PrivateKeyToAddress(pk)
+	if p.Owner == "" {
+		p.Owner = owner
+	} else if p.Owner != owner {
+		return nil, fmt.Errorf("private key address %s mismatch owner %s", owner, p.Owner)
+	}
+	message, err := NewSPLTransferMessage(&p, recentBlockhash, opt)
+	if nil != err {
+		return nil, err
+	}
+	return SignMessage(message, pk)
+}
@@ -0,0 +1,87 @@
+package sol
+
+import (
+	"encoding/binary"
+	"github.com/blocto/solana-go-sdk/common"
+	"testing"
+)
+
+func TestTransferFee(t *testing.T) {
+	fee := &TransferFee{BasisPoints: 50, MaximumFee: 10_000}
+	for amount, expect := range map[uint64]uint64{0: 0, 1: 1, 200: 1, 201: 2, 1_000_000: 5000, 100_000_000: 10_000} {
+		if got := fee.Fee(amount); got != expect {
+			t.Fatalf("fee(%d) = %d, expect %d", amount, got, expect)
+		}
+	}
+	full := &TransferFee{BasisPoints: 10_000, MaximumFee: ^uint64(0)}
+	if got := full.Fee(^uint64(0)); got != ^uint64(0) {
+		t.Fatalf("fee overflow %d", got)
+	}
+}
+
+func TestTransferSPL(t *testing.T) {
+	mint := "4zMMC9srt5Ri5X14GAgXhaHii3GnPAEERYPJgZJDncDU"
+	owner, to, mintKey := common.PublicKeyFromString(testFrom), common.PublicKeyFromString(testTo), common.PublicKeyFromString(mint)
+	ata, _, _ := FindAssociatedTokenAddressWithProgram(owner, mintKey, TokenProgramID)
+	if expect, _, _ := FindAssociatedTokenAddress(owner, mintKey); ata != expect {
+		t.Fatal("token program ata mismatch")
+	}
+
+	signed, err := TransferSPL(testPrivateKey, &SPLTransfer{To: testTo, Mint: mint, Amount: 1_500_000, Decimals: 6, CreateAta: true}, testBlockhash, nil)
+	if nil != err {
+		t.Fatal(err)
+	}
+	tx, _ := DecodeTx(signed.Base64)
+	instructions := tx.Message.DecompileInstructions()
+	toAta, _, _ := FindAssociatedTokenAddress(to, mintKey)
+	if len(instructions) != 2 || instructions[0].ProgramID != common.SPLAssociatedTokenAccountProgramID || instructions[0].Data[0] != 1 ||
+		instructions[0].Accounts[1].PubKey != toAta {
+		t.Fatalf("create ata %+v", instructions[0])
+	}
+	// TransferChecked: 指令序号 12 + u64 amount + u8 decimals
+	transfer := instructions[1]
+	if transfer.ProgramID != TokenProgramID || transfer.Data[0] != 12 || binary.LittleEndian.Uint64(transfer.Data[1:]) != 1_500_000 ||
+		transfer.Data[9] != 6 || transfer.Accounts[0].PubKey != ata || transfer.Accounts[1].PubKey != mintKey || transfer.Accounts[2].PubKey != toAta {
+		t.Fatalf("transfer %+v", transfer)
+	}
+}
+
+func TestTransferSPLToken2022(t *testing.T) {
+	mint := "4zMMC9srt5Ri5X14GAgXhaHii3GnPAEERYPJgZJDncDU"
+	param := &SPLTransfer{
+		Owner:        testFrom,
+		To:           testTo,
+		Mint:         mint,
+		Amount:       1_000_000,
+		Decimals:     6,
+		TokenProgram: Token2022ProgramID.ToBase58(),
+		CreateAta:    true,
+		TransferFee:  &TransferFee{BasisPoints: 100, MaximumFee: 5000},
+	}
+	instructions, err := param.Instructions()
+	if nil != err {
+		t.Fatal(err)
+	}
+	toAta, _, _ := FindAssociatedTokenAddressWithProgram(common.PublicKeyFromString(testTo), common.PublicKeyFromString(mint), Token2022ProgramID)
+	if legacy, _, _ := FindAssociatedTokenAddress(common.PublicKeyFromString(testTo), common.PublicKeyFromString(mint)); legacy == toAta {
+		t.Fatal("token-2022 ata must differ from token ata")
+	}
+	create := instructions[0]
+	if create.Accounts[1].PubKey != toAta || create.Accounts[5].PubKey != Token2022ProgramID {
+		t.Fatalf("create ata %+v", create)
+	}
+	transfer := instructions[1]
+	if transfer.ProgramID != Token2022ProgramID || len(transfer.Data) != 19 || transfer.Data[0] != 26 || transfer.Data[1] != 1 ||
+		binary.LittleEndian.Uint64(transfer.Data[2:]) != 1_000_000 || transfer.Data[10] != 6 || binary.LittleEndian.Uint64(transfer.Data[11:]) != 5000 {
+		t.Fatalf("transfer data %x", transfer.Data)
+	}
+
+	param.TokenProgram = ""
+	if _, err := param.Instructions(); nil == err {
+		t.Fatal("expected transfer fee with token program error")
+	}
+	param.TransferFee, param.To = nil, testFrom
+	if _, err := param.Instructions(); nil == err {
+		t.Fatal("expected transfer to yourself error")
+	}
+}
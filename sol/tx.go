@@ -0,0 +1,200 @@
+package sol
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/PandaManPMC/base58"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/memo"
+	"github.com/blocto/solana-go-sdk/program/system"
+	"github.com/blocto/solana-go-sdk/program/token"
+	"github.com/blocto/solana-go-sdk/types"
+	"golang.org/x/crypto/ed25519"
+)
+
+// TxOption 可选参数
+type TxOption struct {
+	// Memo 附加 Memo 指令，常用于交易所充值标识
+	Memo string `json:"memo"`
+	// ComputeUnitLimit 为 0 时不设置，内置程序指令按 3000、其他指令按 200000 计算单元
+	ComputeUnitLimit uint32 `json:"computeUnitLimit"`
+	// ComputeUnitPrice 优先费单价（micro-lamports / 计算单元），拥堵时提高可加快打包
+	ComputeUnitPrice uint64 `json:"computeUnitPrice"`
+}
+
+// SignedTx 签名后的交易，Base64 可直接用于 sendTransaction（encoding: base64），Base58 为旧版默认编码
+type SignedTx struct {
+	// Signature 第一个签名（手续费支付者）的 base58，即交易哈希
+	Signature string `json:"signature"`
+	Base58    string `json:"base58"`
+	Base64    string `json:"base64"`
+}
+
+// NewMessage 由指令构建 legacy 消息，recentBlockhash 取自 getLatestBlockhash，约 150 个区块（60~90 秒）内有效
+func NewMessage(feePayer, recentBlockhash string, instructions []types.Instruction, opt *TxOption) (types.Message, error) {
+	payer, err := publicKey(feePayer)
+	if nil != err {
+		return types.Message{}, err
+	}
+	if _, err := publicKey(recentBlockhash); nil != err {
+		return types.Message{}, fmt.Errorf("invalid recent blockhash: %s", recentBlockhash)
+	}
+	if len(instructions) == 0 {
+		return types.Message{}, errors.New("instructions miss")
+	}
+	if opt != nil {
+		if opt.Memo != "" {
+			instructions = append(instructions[:len(instructions):len(instructions)], memo.BuildMemo(memo.BuildMemoParam{Memo: []byte(opt.Memo)}))
+		}
+		// limit 与 price 均为 0 时不插入，调用方可自带 ComputeBudget 指令
+		if opt.ComputeUnitLimit > 0 || opt.ComputeUnitPrice > 0 {
+			if instructions, err = WithComputeBudget(instructions, opt.ComputeUnitLimit, opt.ComputeUnitPrice); nil != err {
+				return types.Message{}, err
+			}
+		}
+	}
+	return types.NewMessage(types.NewMessageParam{
+		FeePayer:        payer,
+		RecentBlockhash: recentBlockhash,
+		Instructions:    instructions,
+	}), nil
+}
+
+// NewTransferMessage SOL 转账，lamports 单位 1e-9 SOL
+func NewTransferMessage(from, to string, lamports uint64, recentBlockhash string, opt *TxOption) (types.Message, error) {
+	if lamports == 0 {
+		return types.Message{}, errors.New("amount must be positive")
+	}
+	fromKey, err := publicKey(from)
+	if nil != err {
+		return types.Message{}, err
+	}
+	toKey, err := publicKey(to)
+	if nil != err {
+		return types.Message{}, err
+	}
+	return NewMessage(from, recentBlockhash, []types.Instruction{system.Transfer(system.TransferParam{
+		From:   fromKey,
+		To:     toKey,
+		Amount: lamports,
+	})}, opt)
+}
+
+// NewTokenTransferMessage SPL Token 转账，fromTokenAccount、toTokenAccount 为代币账户（一般为 ATA），
+// owner 为转出代币账户的所有者并支付手续费，amount 为代币最小单位
+func NewTokenTransferMessage(owner, fromTokenAccount, toTokenAccount string, amount uint64, recentBlockhash string, opt *TxOption) (types.Message, error) {
+	if amount == 0 {
+		return types.Message{}, errors.New("amount must be positive")
+	}
+	keys := make([]common.PublicKey, 3)
+	for i, address := range []string{owner, fromTokenAccount, toTokenAccount} {
+		key, err := publicKey(address)
+		if nil != err {
+			return types.Message{}, err
+		}
+		keys[i] = key
+	}
+	return NewMessage(owner, recentBlockhash, []types.Instruction{token.Transfer(token.TransferParam{
+		From:   keys[1],
+		To:     keys[2],
+		Auth:   keys[0],
+		Amount: amount,
+	})}, opt)
+}
+
+// SignMessage 离线签名，signers 须与消息要求的签名者完全一致（顺序任意）
+func SignMessage(message types.Message, signers ...ed25519.PrivateKey) (*SignedTx, error) {
+	accounts := make([]types.Account, 0, len(signers))
+	for _, signer := range signers {
+		account, err := types.AccountFromBytes(signer)
+		if nil != err {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+	if len(accounts) != int(message.Header.NumRequireSignatures) {
+		return nil, fmt.Errorf("message requires %d signatures, got %d", message.Header.NumRequireSignatures, len(accounts))
+	}
+	tx, err := types.NewTransaction(types.NewTransactionParam{Message: message, Signers: accounts})
+	if nil != err {
+		return nil, err
+	}
+	return NewSignedTx(tx)
+}
+
+// NewSignedTx 序列化已签名交易
+func NewSignedTx(tx types.Transaction) (*SignedTx, error) {
+	for i, signature := range tx.Signatures {
+		if len(signature) != ed25519.SignatureSize || isZero(signature) {
+			return nil, fmt.Errorf("signature %d miss", i)
+		}
+	}
+	b, err := tx.Serialize()
+	if nil != err {
+		return nil, err
+	}
+	return &SignedTx{
+		Signature: base58.Encode(tx.Signatures[0]),
+		Base58:    base58.Encode(b),
+		Base64:    base64.StdEncoding.EncodeToString(b),
+	}, nil
+}
+
+// TransferSOL 构建并签名 SOL 转账，privateHex 为 ImportWallet 返回的 64 字节私钥 hex
+func TransferSOL(privateHex, to string, lamports uint64, recentBlockhash string, opt *TxOption) (*SignedTx, error) {
+	pk, err := ImportPrivateKeyFromHex(privateHex)
+	if nil != err {
+		return nil, err
+	}
+	from, _ := PrivateKeyToAddress(pk)
+	message, err := NewTransferMessage(from, to, lamports, recentBlockhash, opt)
+	if nil != err {
+		return nil, err
+	}
+	return SignMessage(message, pk)
+}
+
+// TransferToken 构建并签名 SPL Token 转账，收款代币账户须已存在
+func TransferToken(privateHex, fromTokenAccount, toTokenAccount string, amount uint64, recentBlockhash string, opt *TxOption) (*SignedTx, error) {
+	pk, err := ImportPrivateKeyFromHex(privateHex)
+	if nil != err {
+		return nil, err
+	}
+	owner, _ := PrivateKeyToAddress(pk)
+	message, err := NewTokenTransferMessage(owner, fromTokenAccount, toTokenAccount, amount, recentBlockhash, opt)
+	if nil != err {
+		return nil, err
+	}
+	return SignMessage(message, pk)
+}
+
+// DecodeTx 解析 base64 或 base58 编码的交易
+func DecodeTx(encoded string) (types.Transaction, error) {
+	if b, err := base64.StdEncoding.DecodeString(encoded); nil == err {
+		if tx, err := types.TransactionDeserialize(b); nil == err {
+			return tx, nil
+		}
+	}
+	b, err := base58.Decode(encoded)
+	if nil != err {
+		return types.Transaction{}, errors.New("tx is neither base64 nor base58")
+	}
+	return types.TransactionDeserialize(b)
+}
+
+func publicKey(address string) (common.PublicKey, error) {
+	if !ValidSolanaAddress(address) {
+		return common.PublicKey{}, fmt.Errorf("invalid solana address: %s", address)
+	}
+	return common.PublicKeyFromString(address), nil
+}
+
+func isZero(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
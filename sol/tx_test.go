@@ -0,0 +1,88 @@
+package sol
+
+import (
+	"crypto/ed25519"
+	"encoding/binary"
+	"github.com/PandaManPMC/base58"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/system"
+	"github.com/blocto/solana-go-sdk/types"
+	"testing"
+)
+
+const (
+	testPrivateKey = "341f58e5e65307ea627763a2d730deb6da5b95aca75ef5de2a4d916accd2dc3316b887a803bff07bcf4cd46281ba1cb6ada25fbf0419b3a9496cc20631ff058f"
+	testFrom       = "2XhAmxTuzV63r3DXv68ZNaJK9dqjhvbQiQQJfNjEf3Tc"
+	testTo         = "2qy1811C9mgVquHUrPuuzRrxFrp3Y7wh5QCZzVvQbZda"
+	testBlockhash  = "EkSnNWid2cvwEVnVx9aBqawnmiCNiDgp3gUdkDPTKN1N"
+)
+
+func TestTransferSOL(t *testing.T) {
+	signed, err := TransferSOL(testPrivateKey, testTo, 1_000_000, testBlockhash, &TxOption{Memo: "uid-1"})
+	if nil != err {
+		t.Fatal(err)
+	}
+	tx, err := DecodeTx(signed.Base64)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if base58.Encode(tx.Signatures[0]) != signed.Signature || tx.Message.RecentBlockHash != testBlockhash {
+		t.Fatalf("tx %+v", tx)
+	}
+	message, _ := tx.Message.Serialize()
+	if !ed25519.Verify(common.PublicKeyFromString(testFrom).Bytes(), message, tx.Signatures[0]) {
+		t.Fatal("signature verify failed")
+	}
+	instructions := tx.Message.DecompileInstructions()
+	if len(instructions) != 2 || instructions[0].ProgramID != common.SystemProgramID || instructions[1].ProgramID != common.MemoProgramID {
+		t.Fatalf("instructions %+v", instructions)
+	}
+	// system Transfer: u32 指令序号 2 + u64 lamports
+	data := instructions[0].Data
+	if binary.LittleEndian.Uint32(data) != 2 || binary.LittleEndian.Uint64(data[4:]) != 1_000_000 || string(instructions[1].Data) != "uid-1" {
+		t.Fatalf("data %x", data)
+	}
+	if base58Tx, _ := DecodeTx(signed.Base58); base58.Encode(base58Tx.Signatures[0]) != signed.Signature {
+		t.Fatal("base58 decode mismatch")
+	}
+}
+
+func TestTransferToken(t *testing.T) {
+	mint := common.PublicKeyFromString("4zMMC9srt5Ri5X14GAgXhaHii3GnPAEERYPJgZJDncDU")
+	fromAta, _, _ := FindAssociatedTokenAddress(common.PublicKeyFromString(testFrom), mint)
+	toAta, _, _ := FindAssociatedTokenAddress(common.PublicKeyFromString(testTo), mint)
+	signed, err := TransferToken(testPrivateKey, fromAta.ToBase58(), toAta.ToBase58(), 1_000_000, testBlockhash, nil)
+	if nil != err {
+		t.Fatal(err)
+	}
+	tx, _ := DecodeTx(signed.Base58)
+	instruction := tx.Message.DecompileInstructions()[0]
+	if instruction.ProgramID != common.TokenProgramID || instruction.Accounts[0].PubKey != fromAta || instruction.Accounts[1].PubKey != toAta ||
+		instruction.Data[0] != 3 || binary.LittleEndian.Uint64(instruction.Data[1:]) != 1_000_000 {
+		t.Fatalf("instruction %+v", instruction)
+	}
+
+	if _, err := TransferToken(testPrivateKey, fromAta.ToBase58(), toAta.ToBase58(), 1, "bad", nil); nil == err {
+		t.Fatal("expected blockhash error")
+	}
+	message, _ := NewTransferMessage(testFrom, testTo, 1, testBlockhash, nil)
+	if _, err := SignMessage(message); nil == err {
+		t.Fatal("expected miss signer error")
+	}
+}
+
+func TestNewMessageKeepsCallerInstructions(t *testing.T) {
+	transfer := system.Transfer(system.TransferParam{
+		From:   common.PublicKeyFromString(testFrom),
+		To:     common.PublicKeyFromString(testTo),
+		Amount: 1,
+	})
+	// 调用方切片有剩余容量，追加 Memo 时不能覆盖其底层数组
+	backing := []types.Instruction{transfer, transfer}
+	if _, err := NewMessage(testFrom, testBlockhash, backing[:1], &TxOption{Memo: "uid-1"}); nil != err {
+		t.Fatal(err)
+	}
+	if backing[1].ProgramID != common.SystemProgramID {
+		t.Fatal("caller instructions overwritten by memo")
+	}
+}
@@ -57,6 +57,9 @@ func (tool *Src20InscriptionTool) _initTool(network *chaincfg.Params, request *S
 }
 
 func (tool *Src20InscriptionTool) buildCommitTx(commitTxPrevOutputList PrevOutputs, inscriptionData *InscriptionData, changeAddress string, revealOutValue, commitFeeRate int64, minChangeValue int64) error {
+	if err := commitTxPrevOutputList.checkCardinal(); err != nil {
+		return err
+	}
 	bf := make([]byte, 0, len(inscriptionData.ContentType)+len(inscriptionData.Body))
 	bf = append(bf, inscriptionData.ContentType...)
 	bf = append(bf, inscriptionData.Body...)
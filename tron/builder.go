@@ -0,0 +1,225 @@
+package tron
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/PandaManPMC/gotron-sdk/pkg/proto/core"
+	"github.com/PandaManPMC/txBuilder/tronWal"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"math/big"
+)
+
+const (
+	// DefaultExpiration 交易有效期（毫秒），与 java-tron 创建交易时的默认值一致
+	DefaultExpiration = int64(60 * 1000)
+	// MaxExpiration 节点允许的最长有效期 24 小时
+	MaxExpiration = int64(24 * 60 * 60 * 1000)
+	// DefaultFeeLimit 合约调用默认 fee_limit 100 TRX（单位 sun）
+	DefaultFeeLimit = int64(100_000_000)
+)
+
+// BlockRef 引用区块，取自 /wallet/getnowblock 的 blockID 与 block_header.raw_data，
+// 一般使用最新固化区块，引用区块须在最近 65536 个区块内
+type BlockRef struct {
+	Number int64 `json:"number"`
+	// Hash blockID，32 字节 hex，前 8 字节为区块高度
+	Hash string `json:"hash"`
+	// Timestamp 区块时间（毫秒）
+	Timestamp int64 `json:"timestamp"`
+}
+
+// BlockRefFromHeader 由区块头计算 blockID：sha256(raw_data) 的前 8 字节替换为区块高度
+func BlockRefFromHeader(raw *core.BlockHeaderRaw) (*BlockRef, error) {
+	rawData, err := proto.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("proto marshal block header error: %v", err)
+	}
+	hash := sha256.Sum256(rawData)
+	binary.BigEndian.PutUint64(hash[:8], uint64(raw.Number))
+	return &BlockRef{Number: raw.Number, Hash: hex.EncodeToString(hash[:]), Timestamp: raw.Timestamp}, nil
+}
+
+// TxOption 可选参数，零值使用默认值
+type TxOption struct {
+	// Expiration 相对引用区块时间的有效期（毫秒）
+	Expiration int64 `json:"expiration"`
+	// Timestamp 交易时间（毫秒），为 0 时取引用区块时间
+	Timestamp int64 `json:"timestamp"`
+	// FeeLimit 合约调用愿意燃烧的最大 TRX（sun）
+	FeeLimit int64  `json:"feeLimit"`
+	Memo     string `json:"memo"`
+	// PermissionId 多签使用的权限 id，0 为 owner
+	PermissionId int32 `json:"permissionId"`
+}
+
+// SignedTx 签名后的交易，SignedHex 为 protobuf 序列化的 hex，可用于 /wallet/broadcasthex
+type SignedTx struct {
+	TxId       string `json:"txID"`
+	RawDataHex string `json:"raw_data_hex"`
+	SignedHex  string `json:"signedHex"`
+}
+
+// NewTransferTx TRX 转账，amount 单位 sun
+func NewTransferTx(block *BlockRef, from, to string, amount int64, opt *TxOption) (*core.Transaction, error) {
+	if amount <= 0 {
+		return nil, errors.New("amount must be positive")
+	}
+	owner, err := decodeAddress(from)
+	if err != nil {
+		return nil, err
+	}
+	toAddress, err := decodeAddress(to)
+	if err != nil {
+		return nil, err
+	}
+	return newTransaction(block, core.Transaction_Contract_TransferContract, &core.TransferContract{
+		OwnerAddress: owner,
+		ToAddress:    toAddress,
+		Amount:       amount,
+	}, opt, false)
+}
+
+// NewTriggerSmartContractTx 合约调用，data 为 ABI 编码的调用数据，callValue 为附带的 TRX（sun）
+func NewTriggerSmartContractTx(block *BlockRef, from, contract string, data []byte, callValue int64, opt *TxOption) (*core.Transaction, error) {
+	owner, err := decodeAddress(from)
+	if err != nil {
+		return nil, err
+	}
+	contractAddress, err := decodeAddress(contract)
+	if err != nil {
+		return nil, err
+	}
+	return newTransaction(block, core.Transaction_Contract_TriggerSmartContract, &core.TriggerSmartContract{
+		OwnerAddress:    owner,
+		ContractAddress: contractAddress,
+		CallValue:       callValue,
+		Data:            data,
+	}, opt, true)
+}
+
+// NewTRC20TransferTx TRC-20 transfer(to, amount)，amount 为代币最小单位
+func NewTRC20TransferTx(block *BlockRef, from, contract, to string, amount *big.Int, opt *TxOption) (*core.Transaction, error) {
+	if amount == nil || amount.Sign() <= 0 {
+		return nil, errors.New("amount must be positive")
+	}
+	dataHex, err := tronWal.TRC20TransferData(to, amount)
+	if err != nil {
+		return nil, err
+	}
+	data, _ := hex.DecodeString(dataHex)
+	return NewTriggerSmartContractTx(block, from, contract, data, 0, opt)
+}
+
+func newTransaction(block *BlockRef, contractType core.Transaction_Contract_ContractType, contract proto.Message, opt *TxOption, smartContract bool) (*core.Transaction, error) {
+	if opt == nil {
+		opt = &TxOption{}
+	}
+	refBlockBytes, refBlockHash, err := block.refBytes()
+	if err != nil {
+		return nil, err
+	}
+	expiration := opt.Expiration
+	if expiration == 0 {
+		expiration = DefaultExpiration
+	}
+	if expiration < 0 || expiration > MaxExpiration {
+		return nil, fmt.Errorf("expiration %d out of range", expiration)
+	}
+	timestamp := opt.Timestamp
+	if timestamp == 0 {
+		timestamp = block.Timestamp
+	}
+	parameter, err := anypb.New(contract)
+	if err != nil {
+		return nil, err
+	}
+	raw := &core.TransactionRaw{
+		RefBlockBytes: refBlockBytes,
+		RefBlockHash:  refBlockHash,
+		Expiration:    block.Timestamp + expiration,
+		Timestamp:     timestamp,
+		Contract: []*core.Transaction_Contract{{
+			Type:         contractType,
+			Parameter:    parameter,
+			PermissionId: opt.PermissionId,
+		}},
+	}
+	if opt.Memo != "" {
+		raw.Data = []byte(opt.Memo)
+	}
+	if smartContract {
+		raw.FeeLimit = opt.FeeLimit
+		if raw.FeeLimit == 0 {
+			raw.FeeLimit = DefaultFeeLimit
+		}
+	}
+	return &core.Transaction{RawData: raw}, nil
+}
+
+// refBytes ref_block_bytes 为区块高度的第 6、7 字节，ref_block_hash 为 blockID 的第 8 到 15 字节
+func (b *BlockRef) refBytes() (refBlockBytes, refBlockHash []byte, err error) {
+	if b == nil {
+		return nil, nil, errors.New("block ref miss")
+	}
+	hash, err := hex.DecodeString(b.Hash)
+	if err != nil || len(hash) != 32 {
+		return nil, nil, fmt.Errorf("invalid block hash: %s", b.Hash)
+	}
+	if int64(binary.BigEndian.Uint64(hash[:8])) != b.Number {
+		return nil, nil, fmt.Errorf("block hash %s not match number %d", b.Hash, b.Number)
+	}
+	number := make([]byte, 8)
+	binary.BigEndian.PutUint64(number, uint64(b.Number))
+	return number[6:8], hash[8:16], nil
+}
+
+func decodeAddress(address string) ([]byte, error) {
+	if !tronWal.ValidAddress(address) {
+		return nil, fmt.Errorf("invalid tron address: %s", address)
+	}
+	return tronWal.DecodeCheck(address)
+}
+
+// TxId 交易 id：sha256(raw_data)
+func TxId(transaction *core.Transaction) (string, error) {
+	rawData, err := proto.Marshal(transaction.GetRawData())
+	if err != nil {
+		return "", fmt.Errorf("proto marshal tx raw data error: %v", err)
+	}
+	hash := sha256.Sum256(rawData)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// Sign 签名并返回 txID 与序列化结果，privateKey 为 hex
+func Sign(transaction *core.Transaction, privateKey string) (*SignedTx, error) {
+	transaction, err := SignTransaction(transaction, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return NewSignedTx(transaction)
+}
+
+// NewSignedTx 序列化交易
+func NewSignedTx(transaction *core.Transaction) (*SignedTx, error) {
+	txId, err := TxId(transaction)
+	if err != nil {
+		return nil, err
+	}
+	rawData, err := proto.Marshal(transaction.GetRawData())
+	if err != nil {
+		return nil, fmt.Errorf("proto marshal tx raw data error: %v", err)
+	}
+	signed, err := proto.Marshal(transaction)
+	if err != nil {
+		return nil, fmt.Errorf("proto marshal tx error: %v", err)
+	}
+	return &SignedTx{
+		TxId:       txId,
+		RawDataHex: hex.EncodeToString(rawData),
+		SignedHex:  hex.EncodeToString(signed),
+	}, nil
+}
@@ -0,0 +1,109 @@
+package tron
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/PandaManPMC/gotron-sdk/pkg/proto/core"
+	"github.com/PandaManPMC/txBuilder/tronWal"
+	"github.com/ethereum/go-ethereum/crypto"
+	"google.golang.org/protobuf/proto"
+	"math/big"
+	"testing"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+var testBlock = &BlockRef{
+	Number:    66000000,
+	Hash:      "0000000003ef1480c4a76c6ea4d2f1b7a3a2b2c8a1d7e0e6c3b9f7d0d2a1e4f5",
+	Timestamp: 1728000000000,
+}
+
+func testKey(t *testing.T) (string, string) {
+	privateKey, address, err := tronWal.ImportWallet(testMnemonic, 0)
+	if nil != err {
+		t.Fatal(err)
+	}
+	return hex.EncodeToString(crypto.FromECDSA(privateKey)), address
+}
+
+func TestNewTransferTx(t *testing.T) {
+	privateKey, from := testKey(t)
+	tx, err := NewTransferTx(testBlock, from, "TVTV9aEDdszTNYayNBdjpQ7xfXH3DMyzXq", 1_000_000, &TxOption{Memo: "order-1"})
+	if nil != err {
+		t.Fatal(err)
+	}
+	raw := tx.GetRawData()
+	if hex.EncodeToString(raw.RefBlockBytes) != "1480" || hex.EncodeToString(raw.RefBlockHash) != "c4a76c6ea4d2f1b7" {
+		t.Fatalf("ref block %x %x", raw.RefBlockBytes, raw.RefBlockHash)
+	}
+	if raw.Expiration != testBlock.Timestamp+DefaultExpiration || raw.Timestamp != testBlock.Timestamp || raw.FeeLimit != 0 || string(raw.Data) != "order-1" {
+		t.Fatalf("raw %v", raw)
+	}
+
+	signed, err := Sign(tx, privateKey)
+	if nil != err {
+		t.Fatal(err)
+	}
+	rawData, _ := hex.DecodeString(signed.RawDataHex)
+	hash := sha256.Sum256(rawData)
+	if hex.EncodeToString(hash[:]) != signed.TxId {
+		t.Fatal("txID mismatch")
+	}
+	// 反序列化后签名可恢复出发送地址
+	decoded := new(core.Transaction)
+	b, _ := hex.DecodeString(signed.SignedHex)
+	if err := proto.Unmarshal(b, decoded); nil != err {
+		t.Fatal(err)
+	}
+	pubKey, err := crypto.SigToPub(hash[:], decoded.Signature[0])
+	if nil != err {
+		t.Fatal(err)
+	}
+	if tronWal.PubKeyToAddressTron(*pubKey) != from {
+		t.Fatal("signer mismatch")
+	}
+	transfer := new(core.TransferContract)
+	decoded.RawData.Contract[0].Parameter.UnmarshalTo(transfer)
+	if transfer.Amount != 1_000_000 || tronWal.EncodeCheck(transfer.ToAddress) != "TVTV9aEDdszTNYayNBdjpQ7xfXH3DMyzXq" {
+		t.Fatalf("transfer %v", transfer)
+	}
+}
+
+func TestNewTRC20TransferTx(t *testing.T) {
+	privateKey, from := testKey(t)
+	usdt := "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t"
+	tx, err := NewTRC20TransferTx(testBlock, from, usdt, "TVTV9aEDdszTNYayNBdjpQ7xfXH3DMyzXq", big.NewInt(2_500_000), &TxOption{FeeLimit: 30_000_000, Expiration: 10 * 60 * 1000})
+	if nil != err {
+		t.Fatal(err)
+	}
+	raw := tx.GetRawData()
+	if raw.FeeLimit != 30_000_000 || raw.Expiration != testBlock.Timestamp+10*60*1000 {
+		t.Fatalf("raw %v", raw)
+	}
+	trigger := new(core.TriggerSmartContract)
+	raw.Contract[0].Parameter.UnmarshalTo(trigger)
+	padded, _ := tronWal.HexAddressPadded64("TVTV9aEDdszTNYayNBdjpQ7xfXH3DMyzXq")
+	// HexAddressPadded64 带 41 前缀，ABI 参数只保留 20 字节地址
+	expect := "a9059cbb" + "000000000000000000000000" + padded[24:] + tronWal.IntToHexPadded64(2_500_000)
+	if hex.EncodeToString(trigger.Data) != expect || tronWal.EncodeCheck(trigger.ContractAddress) != usdt {
+		t.Fatalf("trigger data %x", trigger.Data)
+	}
+	if _, err := Sign(tx, privateKey); nil != err {
+		t.Fatal(err)
+	}
+
+	if _, err := NewTRC20TransferTx(&BlockRef{Number: 1, Hash: testBlock.Hash}, from, usdt, from, big.NewInt(1), nil); nil == err {
+		t.Fatal("expected block hash number mismatch")
+	}
+}
+
+func TestBlockRefFromHeader(t *testing.T) {
+	ref, err := BlockRefFromHeader(&core.BlockHeaderRaw{Number: 66000000, Timestamp: 1728000000000, ParentHash: make([]byte, 32)})
+	if nil != err {
+		t.Fatal(err)
+	}
+	if ref.Hash[:16] != "0000000003ef1480" {
+		t.Fatalf("block id %s", ref.Hash)
+	}
+}
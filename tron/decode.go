@@ -0,0 +1,363 @@
+package tron
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/PandaManPMC/gotron-sdk/pkg/proto/core"
+	"github.com/PandaManPMC/txBuilder/evmAbi"
+	"github.com/PandaManPMC/txBuilder/tronWal"
+	"google.golang.org/protobuf/proto"
+)
+
+// DecodedTx 解码后的交易，地址均为 base58
+type DecodedTx struct {
+	TxId          string             `json:"txID"`
+	RefBlockBytes string             `json:"ref_block_bytes"`
+	RefBlockHash  string             `json:"ref_block_hash"`
+	Expiration    int64              `json:"expiration"`
+	Timestamp     int64              `json:"timestamp"`
+	FeeLimit      int64              `json:"fee_limit,omitempty"`
+	Memo          string             `json:"memo,omitempty"`
+	Contracts     []*DecodedContract `json:"contracts"`
+	Signers       []string           `json:"signers"`
+}
+
+type DecodedContract struct {
+	Type         string      `json:"type"`
+	PermissionId int32       `json:"permission_id"`
+	Parameter    interface{} `json:"parameter"`
+}
+
+type TransferParameter struct {
+	OwnerAddress string `json:"owner_address"`
+	ToAddress    string `json:"to_address"`
+	Amount       int64  `json:"amount"`
+}
+
+type TriggerSmartContractParameter struct {
+	OwnerAddress    string `json:"owner_address"`
+	ContractAddress string `json:"contract_address"`
+	CallValue       int64  `json:"call_value"`
+	Data            string `json:"data"`
+	// TRC20 data 为 TRC-20 transfer/approve/transferFrom 时的解析结果
+	TRC20 *TRC20Call `json:"trc20,omitempty"`
+}
+
+type TRC20Call struct {
+	Method  string `json:"method"`
+	From    string `json:"from,omitempty"`
+	To      string `json:"to,omitempty"`
+	Spender string `json:"spender,omitempty"`
+	Amount  string `json:"amount"`
+}
+
+type AccountPermissionUpdateParameter struct {
+	OwnerAddress string               `json:"owner_address"`
+	Owner        *DecodedPermission   `json:"owner"`
+	Witness      *DecodedPermission   `json:"witness,omitempty"`
+	Actives      []*DecodedPermission `json:"actives"`
+}
+
+type DecodedPermission struct {
+	Type       string           `json:"type"`
+	Id         int32            `json:"id"`
+	Name       string           `json:"permission_name"`
+	Threshold  int64            `json:"threshold"`
+	Keys       []*PermissionKey `json:"keys"`
+	Operations []string         `json:"operations,omitempty"`
+}
+
+// TransferAssetParameter TRC-10 转账，TokenId 为数字 id；ALLOW_SAME_TOKEN_NAME 之前的旧交易为代币名称
+type TransferAssetParameter struct {
+	OwnerAddress string `json:"owner_address"`
+	ToAddress    string `json:"to_address"`
+	TokenId      string `json:"asset_name"`
+	Amount       int64  `json:"amount"`
+}
+
+type AssetIssueParameter struct {
+	OwnerAddress string `json:"owner_address"`
+	AssetIssue
+}
+
+// StakeParameter 质押、解质押、代理资源与收回代理
+type StakeParameter struct {
+	OwnerAddress    string `json:"owner_address"`
+	ReceiverAddress string `json:"receiver_address,omitempty"`
+	Resource        string `json:"resource"`
+	Balance         int64  `json:"balance"`
+	Lock            bool   `json:"lock,omitempty"`
+	LockPeriod      int64  `json:"lock_period,omitempty"`
+}
+
+type VoteWitnessParameter struct {
+	OwnerAddress string  `json:"owner_address"`
+	Votes        []*Vote `json:"votes"`
+}
+
+// OwnerParameter 只有 owner_address 的合约，如 WithdrawExpireUnfreeze、WithdrawBalance
+type OwnerParameter struct {
+	OwnerAddress string `json:"owner_address"`
+}
+
+// UnknownParameter 未支持解析的合约，Value 为 protobuf hex
+type UnknownParameter struct {
+	TypeUrl string `json:"type_url"`
+	Value   string `json:"value"`
+}
+
+// DecodeSignedHex 解码 protobuf hex 交易
+func DecodeSignedHex(signedHex string) (*DecodedTx, error) {
+	b, err := hex.DecodeString(signedHex)
+	if err != nil {
+		return nil, fmt.Errorf("decode hex error: %v", err)
+	}
+	transaction := new(core.Transaction)
+	if err = proto.Unmarshal(b, transaction); err != nil {
+		return nil, fmt.Errorf("proto unmarshal tx error: %v", err)
+	}
+	return DecodeTransaction(transaction)
+}
+
+// DecodeTransaction 计算 txID、恢复签名者并解码合约参数
+func DecodeTransaction(transaction *core.Transaction) (*DecodedTx, error) {
+	raw := transaction.GetRawData()
+	if raw == nil {
+		return nil, fmt.Errorf("tx raw data miss")
+	}
+	txId, err := TxId(transaction)
+	if err != nil {
+		return nil, err
+	}
+	signers, err := RecoverSigners(transaction)
+	if err != nil {
+		return nil, err
+	}
+	decoded := &DecodedTx{
+		TxId:          txId,
+		RefBlockBytes: hex.EncodeToString(raw.RefBlockBytes),
+		RefBlockHash:  hex.EncodeToString(raw.RefBlockHash),
+		Expiration:    raw.Expiration,
+		Timestamp:     raw.Timestamp,
+		FeeLimit:      raw.FeeLimit,
+		Memo:          string(raw.Data),
+		Signers:       signers,
+	}
+	for _, contract := range raw.Contract {
+		parameter, err := decodeContractParameter(contract)
+		if err != nil {
+			return nil, err
+		}
+		decoded.Contracts = append(decoded.Contracts, &DecodedContract{
+			Type:         contract.Type.String(),
+			PermissionId: contract.PermissionId,
+			Parameter:    parameter,
+		})
+	}
+	return decoded, nil
+}
+
+// DecodeTransactionJson DecodeTransaction 的 JSON 输出
+func DecodeTransactionJson(transaction *core.Transaction) (string, error) {
+	decoded, err := DecodeTransaction(transaction)
+	if err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(decoded)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func decodeContractParameter(contract *core.Transaction_Contract) (interface{}, error) {
+	switch contract.Type {
+	case core.Transaction_Contract_TransferContract:
+		c := new(core.TransferContract)
+		if err := contract.Parameter.UnmarshalTo(c); err != nil {
+			return nil, err
+		}
+		return &TransferParameter{
+			OwnerAddress: encodeAddress(c.OwnerAddress),
+			ToAddress:    encodeAddress(c.ToAddress),
+			Amount:       c.Amount,
+		}, nil
+	case core.Transaction_Contract_TriggerSmartContract:
+		c := new(core.TriggerSmartContract)
+		if err := contract.Parameter.UnmarshalTo(c); err != nil {
+			return nil, err
+		}
+		return &TriggerSmartContractParameter{
+			OwnerAddress:    encodeAddress(c.OwnerAddress),
+			ContractAddress: encodeAddress(c.ContractAddress),
+			CallValue:       c.CallValue,
+			Data:            hex.EncodeToString(c.Data),
+			TRC20:           decodeTRC20Call(c.Data),
+		}, nil
+	case core.Transaction_Contract_AccountPermissionUpdateContract:
+		c := new(core.AccountPermissionUpdateContract)
+		if err := contract.Parameter.UnmarshalTo(c); err != nil {
+			return nil, err
+		}
+		parameter := &AccountPermissionUpdateParameter{
+			OwnerAddress: encodeAddress(c.OwnerAddress),
+			Owner:        decodePermission(c.Owner),
+			Witness:      decodePermission(c.Witness),
+		}
+		for _, active := range c.Actives {
+			parameter.Actives = append(parameter.Actives, decodePermission(active))
+		}
+		return parameter, nil
+	case core.Transaction_Contract_TransferAssetContract:
+		c := new(core.TransferAssetContract)
+		if err := contract.Parameter.UnmarshalTo(c); err != nil {
+			return nil, err
+		}
+		return &TransferAssetParameter{
+			OwnerAddress: encodeAddress(c.OwnerAddress),
+			ToAddress:    encodeAddress(c.ToAddress),
+			TokenId:      string(c.AssetName),
+			Amount:       c.Amount,
+		}, nil
+	case core.Transaction_Contract_AssetIssueContract:
+		c := new(core.AssetIssueContract)
+		if err := contract.Parameter.UnmarshalTo(c); err != nil {
+			return nil, err
+		}
+		parameter := &AssetIssueParameter{
+			OwnerAddress: encodeAddress(c.OwnerAddress),
+			AssetIssue: AssetIssue{
+				Name:                    string(c.Name),
+				Abbr:                    string(c.Abbr),
+				TotalSupply:             c.TotalSupply,
+				Precision:               c.Precision,
+				TrxNum:                  c.TrxNum,
+				Num:                     c.Num,
+				StartTime:               c.StartTime,
+				EndTime:                 c.EndTime,
+				Description:             string(c.Description),
+				Url:                     string(c.Url),
+				FreeAssetNetLimit:       c.FreeAssetNetLimit,
+				PublicFreeAssetNetLimit: c.PublicFreeAssetNetLimit,
+			},
+		}
+		for _, frozen := range c.FrozenSupply {
+			parameter.Frozen = append(parameter.Frozen, &FrozenSupply{Amount: frozen.FrozenAmount, Days: frozen.FrozenDays})
+		}
+		return parameter, nil
+	case core.Transaction_Contract_FreezeBalanceV2Contract:
+		c := new(core.FreezeBalanceV2Contract)
+		if err := contract.Parameter.UnmarshalTo(c); err != nil {
+			return nil, err
+		}
+		return &StakeParameter{OwnerAddress: encodeAddress(c.OwnerAddress), Resource: c.Resource.String(), Balance: c.FrozenBalance}, nil
+	case core.Transaction_Contract_UnfreezeBalanceV2Contract:
+		c := new(core.UnfreezeBalanceV2Contract)
+		if err := contract.Parameter.UnmarshalTo(c); err != nil {
+			return nil, err
+		}
+		return &StakeParameter{OwnerAddress: encodeAddress(c.OwnerAddress), Resource: c.Resource.String(), Balance: c.UnfreezeBalance}, nil
+	case core.Transaction_Contract_DelegateResourceContract:
+		c := new(core.DelegateResourceContract)
+		if err := contract.Parameter.UnmarshalTo(c); err != nil {
+			return nil, err
+		}
+		return &StakeParameter{
+			OwnerAddress:    encodeAddress(c.OwnerAddress),
+			ReceiverAddress: encodeAddress(c.ReceiverAddress),
+			Resource:        c.Resource.String(),
+			Balance:         c.Balance,
+			Lock:            c.Lock,
+			LockPeriod:      c.LockPeriod,
+		}, nil
+	case core.Transaction_Contract_UnDelegateResourceContract:
+		c := new(core.UnDelegateResourceContract)
+		if err := contract.Parameter.UnmarshalTo(c); err != nil {
+			return nil, err
+		}
+		return &StakeParameter{
+			OwnerAddress:    encodeAddress(c.OwnerAddress),
+			ReceiverAddress: encodeAddress(c.ReceiverAddress),
+			Resource:        c.Resource.String(),
+			Balance:         c.Balance,
+		}, nil
+	case core.Transaction_Contract_VoteWitnessContract:
+		c := new(core.VoteWitnessContract)
+		if err := contract.Parameter.UnmarshalTo(c); err != nil {
+			return nil, err
+		}
+		parameter := &VoteWitnessParameter{OwnerAddress: encodeAddress(c.OwnerAddress)}
+		for _, vote := range c.Votes {
+			parameter.Votes = append(parameter.Votes, &Vote{Address: encodeAddress(vote.VoteAddress), Count: vote.VoteCount})
+		}
+		return parameter, nil
+	case core.Transaction_Contract_WithdrawExpireUnfreezeContract:
+		c := new(core.WithdrawExpireUnfreezeContract)
+		if err := contract.Parameter.UnmarshalTo(c); err != nil {
+			return nil, err
+		}
+		return &OwnerParameter{OwnerAddress: encodeAddress(c.OwnerAddress)}, nil
+	case core.Transaction_Contract_CancelAllUnfreezeV2Contract:
+		c := new(core.CancelAllUnfreezeV2Contract)
+		if err := contract.Parameter.UnmarshalTo(c); err != nil {
+			return nil, err
+		}
+		return &OwnerParameter{OwnerAddress: encodeAddress(c.OwnerAddress)}, nil
+	case core.Transaction_Contract_WithdrawBalanceContract:
+		c := new(core.WithdrawBalanceContract)
+		if err := contract.Parameter.UnmarshalTo(c); err != nil {
+			return nil, err
+		}
+		return &OwnerParameter{OwnerAddress: encodeAddress(c.OwnerAddress)}, nil
+	}
+	return &UnknownParameter{
+		TypeUrl: contract.GetParameter().GetTypeUrl(),
+		Value:   hex.EncodeToString(contract.GetParameter().GetValue()),
+	}, nil
+}
+
+func decodeTRC20Call(data []byte) *TRC20Call {
+	call, err := evmAbi.DecodeTokenCall(data)
+	if err != nil {
+		return nil
+	}
+	switch call.Method {
+	case evmAbi.SigTransfer:
+		return &TRC20Call{Method: "transfer", To: tronWal.EvmToTronAddress(call.To), Amount: call.Amount.String()}
+	case evmAbi.SigApprove:
+		return &TRC20Call{Method: "approve", Spender: tronWal.EvmToTronAddress(call.Spender), Amount: call.Amount.String()}
+	case evmAbi.SigTransferFrom:
+		return &TRC20Call{Method: "transferFrom", From: tronWal.EvmToTronAddress(call.From), To: tronWal.EvmToTronAddress(call.To), Amount: call.Amount.String()}
+	}
+	return nil
+}
+
+func decodePermission(permission *core.Permission) *DecodedPermission {
+	if permission == nil {
+		return nil
+	}
+	decoded := &DecodedPermission{
+		Type:      permission.Type.String(),
+		Id:        permission.Id,
+		Name:      permission.PermissionName,
+		Threshold: permission.Threshold,
+	}
+	for _, key := range permission.Keys {
+		decoded.Keys = append(decoded.Keys, &PermissionKey{Address: encodeAddress(key.Address), Weight: key.Weight})
+	}
+	for i := 0; i < len(permission.Operations)*8; i++ {
+		contractType := core.Transaction_Contract_ContractType(i)
+		if operationAllowed(permission.Operations, contractType) {
+			decoded.Operations = append(decoded.Operations, contractType.String())
+		}
+	}
+	return decoded
+}
+
+func encodeAddress(address []byte) string {
+	if len(address) == 0 {
+		return ""
+	}
+	return tronWal.EncodeCheck(address)
+}
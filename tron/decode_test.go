@@ -0,0 +1,95 @@
+package tron
+
+import (
+	"encoding/hex"
+	"github.com/PandaManPMC/gotron-sdk/pkg/proto/core"
+	"github.com/PandaManPMC/txBuilder/tronWal"
+	"github.com/ethereum/go-ethereum/crypto"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestDecodeTransaction(t *testing.T) {
+	privateKey, from := testKey(t)
+	usdt := "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t"
+	tx, err := NewTRC20TransferTx(testBlock, from, usdt, "TVTV9aEDdszTNYayNBdjpQ7xfXH3DMyzXq", big.NewInt(2_500_000), nil)
+	if nil != err {
+		t.Fatal(err)
+	}
+	signed, err := Sign(tx, privateKey)
+	if nil != err {
+		t.Fatal(err)
+	}
+	decoded, err := DecodeSignedHex(signed.SignedHex)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if decoded.TxId != signed.TxId || len(decoded.Signers) != 1 || decoded.Signers[0] != from || decoded.FeeLimit != DefaultFeeLimit {
+		t.Fatalf("decoded %+v", decoded)
+	}
+	parameter := decoded.Contracts[0].Parameter.(*TriggerSmartContractParameter)
+	if decoded.Contracts[0].Type != "TriggerSmartContract" || parameter.ContractAddress != usdt || parameter.OwnerAddress != from ||
+		parameter.TRC20.To != "TVTV9aEDdszTNYayNBdjpQ7xfXH3DMyzXq" || parameter.TRC20.Amount != "2500000" {
+		t.Fatalf("parameter %+v %+v", parameter, parameter.TRC20)
+	}
+
+	tx, _ = NewTransferTx(testBlock, from, usdt, 5, nil)
+	js, err := DecodeTransactionJson(tx)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if !strings.Contains(js, `"type":"TransferContract"`) || !strings.Contains(js, `"amount":5`) {
+		t.Fatalf("json %s", js)
+	}
+}
+
+func TestCheckPermission(t *testing.T) {
+	keys := make([]string, 3)
+	addresses := make([]string, 3)
+	for i := range keys {
+		privateKey, address, err := tronWal.ImportWallet(testMnemonic, i)
+		if nil != err {
+			t.Fatal(err)
+		}
+		keys[i], addresses[i] = hex.EncodeToString(crypto.FromECDSA(privateKey)), address
+	}
+	permissionKey := func(address string, weight int64) *core.Key {
+		b, _ := tronWal.DecodeCheck(address)
+		return &core.Key{Address: b, Weight: weight}
+	}
+	operations := make([]byte, 32)
+	operations[0] = 1 << core.Transaction_Contract_TransferContract
+	permission := &core.Permission{
+		Type:       core.Permission_Active,
+		Id:         2,
+		Threshold:  3,
+		Operations: operations,
+		Keys:       []*core.Key{permissionKey(addresses[0], 1), permissionKey(addresses[1], 2)},
+	}
+
+	tx, _ := NewTransferTx(testBlock, addresses[0], "TVTV9aEDdszTNYayNBdjpQ7xfXH3DMyzXq", 5, &TxOption{PermissionId: 2})
+	SignTransaction(tx, keys[0])
+	check, err := CheckPermission(tx, permission)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if check.Weight != 1 || check.Satisfied {
+		t.Fatalf("check %+v", check)
+	}
+	SignTransaction(tx, keys[1])
+	check, _ = CheckPermission(tx, permission)
+	if check.Weight != 3 || !check.Satisfied {
+		t.Fatalf("check %+v", check)
+	}
+	SignTransaction(tx, keys[2])
+	if _, err := CheckPermission(tx, permission); nil == err {
+		t.Fatal("expected signer not in permission")
+	}
+
+	// active 权限不含 TriggerSmartContract 操作位
+	tx, _ = NewTRC20TransferTx(testBlock, addresses[0], "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t", addresses[1], big.NewInt(1), &TxOption{PermissionId: 2})
+	if _, err := CheckPermission(tx, permission); nil == err {
+		t.Fatal("expected operation not allowed")
+	}
+}
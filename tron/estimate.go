@@ -0,0 +1,219 @@
+package tron
+
+import (
+	"errors"
+	"fmt"
+	"github.com/PandaManPMC/gotron-sdk/pkg/proto/core"
+	"github.com/PandaManPMC/txBuilder/evmAbi"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// maxResultSizeInTx 节点计算带宽时为每个合约的执行结果预留的字节数
+	maxResultSizeInTx = 64
+	// signatureSize 单个签名序列化后的字节数：tag 1 + 长度 1 + 65
+	signatureSize = 67
+
+	// TRC20TransferEnergy 收款地址已持有该代币时 USDT transfer 的能量消耗（参考值）
+	TRC20TransferEnergy = int64(65_000)
+	// TRC20TransferNewHolderEnergy 收款地址余额为 0 时需新建存储，能量约翻倍
+	TRC20TransferNewHolderEnergy = int64(130_000)
+	// DefaultFeeLimitMargin fee_limit 在预估能量基础上上浮的百分比
+	DefaultFeeLimitMargin = int64(20)
+)
+
+// ChainParams 链参数，对应 /wallet/getchainparameters，单位 sun
+type ChainParams struct {
+	// EnergyFee getEnergyFee 每单位能量燃烧的 sun
+	EnergyFee int64 `json:"energyFee"`
+	// TransactionFee getTransactionFee 每字节带宽燃烧的 sun
+	TransactionFee int64 `json:"transactionFee"`
+	// CreateAccountFee getCreateAccountFee 激活账户时带宽不足燃烧的 sun
+	CreateAccountFee int64 `json:"createAccountFee"`
+	// CreateNewAccountFeeInSystemContract getCreateNewAccountFeeInSystemContract 转账激活新账户额外燃烧的 sun
+	CreateNewAccountFeeInSystemContract int64 `json:"createNewAccountFeeInSystemContract"`
+	// MaxFeeLimit getMaxFeeLimit
+	MaxFeeLimit int64 `json:"maxFeeLimit"`
+}
+
+// DefaultChainParams 主网当前参数，参数可由提案修改，能联网时以 ChainParamsFromMap 读取的为准
+func DefaultChainParams() *ChainParams {
+	return &ChainParams{
+		EnergyFee:                           210,
+		TransactionFee:                      1000,
+		CreateAccountFee:                    100_000,
+		CreateNewAccountFeeInSystemContract: 1_000_000,
+		MaxFeeLimit:                         15_000_000_000,
+	}
+}
+
+// ChainParamsFromMap 由 getchainparameters 返回的 key/value 构造，缺少的参数使用 DefaultChainParams
+func ChainParamsFromMap(parameters map[string]int64) *ChainParams {
+	params := DefaultChainParams()
+	for key, field := range map[string]*int64{
+		"getEnergyFee":                           &params.EnergyFee,
+		"getTransactionFee":                      &params.TransactionFee,
+		"getCreateAccountFee":                    &params.CreateAccountFee,
+		"getCreateNewAccountFeeInSystemContract": &params.CreateNewAccountFeeInSystemContract,
+		"getMaxFeeLimit":                         &params.MaxFeeLimit,
+	} {
+		if value, ok := parameters[key]; ok {
+			*field = value
+		}
+	}
+	return params
+}
+
+// AccountResource 账户资源快照，对应 /wallet/getaccountresource 与账户余额，字段缺省为 0
+type AccountResource struct {
+	FreeNetLimit int64 `json:"freeNetLimit"`
+	FreeNetUsed  int64 `json:"freeNetUsed"`
+	NetLimit     int64 `json:"NetLimit"`
+	NetUsed      int64 `json:"NetUsed"`
+	EnergyLimit  int64 `json:"EnergyLimit"`
+	EnergyUsed   int64 `json:"EnergyUsed"`
+	// Balance 账户 TRX 余额（sun）
+	Balance int64 `json:"balance"`
+}
+
+// EstimateOption 可选参数
+type EstimateOption struct {
+	// Energy 合约调用消耗的能量，取自 /wallet/estimateenergy 或 triggerconstantcontract 的 energy_used；
+	// 为 0 时 TRC-20 transfer 使用 TRC20TransferEnergy 参考值
+	Energy int64 `json:"energy"`
+	// NewAccount TRX 转账的收款地址未激活
+	NewAccount bool `json:"newAccount"`
+	// Signatures 签名数量，交易未签名时用于计算签名字节，为 0 时取 1；已签名的交易按实际签名计算
+	Signatures int `json:"signatures"`
+	// FeeLimitMargin fee_limit 上浮百分比，为 0 时取 DefaultFeeLimitMargin
+	FeeLimitMargin int64 `json:"feeLimitMargin"`
+}
+
+// Estimate 资源预估结果，单位 sun
+type Estimate struct {
+	// Bandwidth 交易消耗的带宽（字节）
+	Bandwidth int64 `json:"bandwidth"`
+	// BandwidthBurn 带宽不足时燃烧的 TRX，带宽不足时按全部字节燃烧，不会部分抵扣
+	BandwidthBurn int64 `json:"bandwidthBurn"`
+	Energy        int64 `json:"energy"`
+	// EnergyBurn 质押能量不足部分燃烧的 TRX
+	EnergyBurn int64 `json:"energyBurn"`
+	// AccountBurn 激活新账户燃烧的 TRX
+	AccountBurn int64 `json:"accountBurn"`
+	// TotalBurn 预计共燃烧的 TRX
+	TotalBurn int64 `json:"totalBurn"`
+	// MinFeeLimit 刚好覆盖预估能量的 fee_limit，节点按 fee_limit/EnergyFee 限制全部能量（含质押能量）
+	MinFeeLimit int64 `json:"minFeeLimit"`
+	// FeeLimit 推荐的 fee_limit，在 MinFeeLimit 基础上上浮，不超过 MaxFeeLimit
+	FeeLimit int64 `json:"feeLimit"`
+	// Sufficient 余额是否足够支付 TotalBurn 与转出的 TRX
+	Sufficient bool `json:"sufficient"`
+}
+
+// EstimateResource 预估交易的带宽、能量与燃烧的 TRX，并给出 fee_limit 建议。
+// 带宽优先消耗质押带宽，其次免费带宽；激活新账户不能使用免费带宽
+func EstimateResource(transaction *core.Transaction, account *AccountResource, params *ChainParams, opt *EstimateOption) (*Estimate, error) {
+	raw := transaction.GetRawData()
+	if raw == nil || len(raw.Contract) == 0 {
+		return nil, errors.New("tx raw data miss")
+	}
+	if account == nil {
+		account = &AccountResource{}
+	}
+	if params == nil {
+		params = DefaultChainParams()
+	}
+	if opt == nil {
+		opt = &EstimateOption{}
+	}
+	bandwidth, err := TxBandwidth(transaction, opt.Signatures)
+	if err != nil {
+		return nil, err
+	}
+	estimate := &Estimate{Bandwidth: bandwidth}
+
+	stakedNet := max(account.NetLimit-account.NetUsed, 0)
+	freeNet := max(account.FreeNetLimit-account.FreeNetUsed, 0)
+	if opt.NewAccount {
+		estimate.AccountBurn = params.CreateNewAccountFeeInSystemContract
+		if stakedNet < bandwidth {
+			estimate.AccountBurn += params.CreateAccountFee
+		}
+	} else if stakedNet < bandwidth && freeNet < bandwidth {
+		estimate.BandwidthBurn = bandwidth * params.TransactionFee
+	}
+
+	contract := raw.Contract[0]
+	var callValue int64
+	switch contract.Type {
+	case core.Transaction_Contract_TransferContract:
+		c := new(core.TransferContract)
+		if err := contract.Parameter.UnmarshalTo(c); err != nil {
+			return nil, err
+		}
+		callValue = c.Amount
+	case core.Transaction_Contract_TriggerSmartContract, core.Transaction_Contract_CreateSmartContract:
+		estimate.Energy = opt.Energy
+		if contract.Type == core.Transaction_Contract_TriggerSmartContract {
+			c := new(core.TriggerSmartContract)
+			if err := contract.Parameter.UnmarshalTo(c); err != nil {
+				return nil, err
+			}
+			callValue = c.CallValue
+			if estimate.Energy == 0 {
+				if call, err := evmAbi.DecodeTokenCall(c.Data); err == nil && call.Method == evmAbi.SigTransfer {
+					estimate.Energy = TRC20TransferEnergy
+				}
+			}
+		}
+		if estimate.Energy == 0 {
+			return nil, fmt.Errorf("%s miss energy", contract.Type)
+		}
+	}
+
+	if estimate.Energy > 0 {
+		if params.EnergyFee <= 0 {
+			return nil, errors.New("energy fee must be positive")
+		}
+		stakedEnergy := max(account.EnergyLimit-account.EnergyUsed, 0)
+		estimate.EnergyBurn = max(estimate.Energy-stakedEnergy, 0) * params.EnergyFee
+		estimate.MinFeeLimit = estimate.Energy * params.EnergyFee
+		margin := opt.FeeLimitMargin
+		if margin == 0 {
+			margin = DefaultFeeLimitMargin
+		}
+		estimate.FeeLimit = estimate.MinFeeLimit * (100 + margin) / 100
+		if params.MaxFeeLimit > 0 {
+			if estimate.MinFeeLimit > params.MaxFeeLimit {
+				return nil, fmt.Errorf("energy %d exceeds max fee limit %d", estimate.Energy, params.MaxFeeLimit)
+			}
+			estimate.FeeLimit = min(estimate.FeeLimit, params.MaxFeeLimit)
+		}
+	}
+	estimate.TotalBurn = estimate.BandwidthBurn + estimate.EnergyBurn + estimate.AccountBurn
+	estimate.Sufficient = account.Balance >= estimate.TotalBurn+callValue
+	return estimate, nil
+}
+
+// TxBandwidth 交易消耗的带宽：序列化后的字节数 + 每个合约预留的 64 字节结果，未签名时按 signatures 个签名补齐
+func TxBandwidth(transaction *core.Transaction, signatures int) (int64, error) {
+	rawData, err := proto.Marshal(transaction.GetRawData())
+	if err != nil {
+		return 0, fmt.Errorf("proto marshal tx raw data error: %v", err)
+	}
+	// field 1 raw_data 的 tag 与长度前缀
+	size := 1 + protowire.SizeVarint(uint64(len(rawData))) + len(rawData)
+	if len(transaction.GetSignature()) > 0 {
+		for _, signature := range transaction.GetSignature() {
+			size += protowire.SizeBytes(len(signature)) + 1
+		}
+	} else {
+		if signatures <= 0 {
+			signatures = 1
+		}
+		size += signatures * signatureSize
+	}
+	size += len(transaction.GetRawData().GetContract()) * maxResultSizeInTx
+	return int64(size), nil
+}
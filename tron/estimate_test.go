@@ -0,0 +1,67 @@
+package tron
+
+import (
+	"google.golang.org/protobuf/proto"
+	"math/big"
+	"testing"
+)
+
+func TestTxBandwidth(t *testing.T) {
+	privateKey, from := testKey(t)
+	tx, _ := NewTransferTx(testBlock, from, "TVTV9aEDdszTNYayNBdjpQ7xfXH3DMyzXq", 1_000_000, nil)
+	unsigned, err := TxBandwidth(tx, 1)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if _, err := Sign(tx, privateKey); nil != err {
+		t.Fatal(err)
+	}
+	signed, _ := TxBandwidth(tx, 0)
+	if unsigned != signed || signed != int64(proto.Size(tx))+maxResultSizeInTx {
+		t.Fatalf("bandwidth unsigned %d signed %d size %d", unsigned, signed, proto.Size(tx))
+	}
+}
+
+func TestEstimateResource(t *testing.T) {
+	_, from := testKey(t)
+	params := DefaultChainParams()
+	tx, _ := NewTRC20TransferTx(testBlock, from, "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t", "TVTV9aEDdszTNYayNBdjpQ7xfXH3DMyzXq", big.NewInt(1), nil)
+	bandwidth, _ := TxBandwidth(tx, 1)
+
+	// 免费带宽足够，质押能量 20000
+	estimate, err := EstimateResource(tx, &AccountResource{FreeNetLimit: 600, EnergyLimit: 20_000, Balance: 100_000_000}, params, nil)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if estimate.Bandwidth != bandwidth || estimate.BandwidthBurn != 0 || estimate.Energy != TRC20TransferEnergy {
+		t.Fatalf("estimate %+v", estimate)
+	}
+	if estimate.EnergyBurn != (TRC20TransferEnergy-20_000)*210 || estimate.MinFeeLimit != TRC20TransferEnergy*210 ||
+		estimate.FeeLimit != TRC20TransferEnergy*210*120/100 || !estimate.Sufficient {
+		t.Fatalf("estimate %+v", estimate)
+	}
+
+	// 无资源时带宽按全部字节燃烧
+	estimate, _ = EstimateResource(tx, &AccountResource{FreeNetLimit: 600, FreeNetUsed: 600, Balance: 1_000_000}, params, &EstimateOption{Energy: 130_000})
+	if estimate.BandwidthBurn != bandwidth*1000 || estimate.EnergyBurn != 130_000*210 || estimate.Sufficient {
+		t.Fatalf("estimate %+v", estimate)
+	}
+
+	transfer, _ := NewTransferTx(testBlock, from, "TVTV9aEDdszTNYayNBdjpQ7xfXH3DMyzXq", 5_000_000, nil)
+	estimate, _ = EstimateResource(transfer, &AccountResource{FreeNetLimit: 600, Balance: 6_000_000}, params, &EstimateOption{NewAccount: true})
+	if estimate.AccountBurn != 1_100_000 || estimate.FeeLimit != 0 || estimate.Sufficient {
+		t.Fatalf("estimate %+v", estimate)
+	}
+
+	trigger, _ := NewTriggerSmartContractTx(testBlock, from, "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t", []byte{1, 2, 3, 4}, 0, nil)
+	if _, err := EstimateResource(trigger, nil, params, nil); nil == err {
+		t.Fatal("expected miss energy error")
+	}
+}
+
+func TestChainParamsFromMap(t *testing.T) {
+	params := ChainParamsFromMap(map[string]int64{"getEnergyFee": 420, "getTransactionFee": 1000})
+	if params.EnergyFee != 420 || params.MaxFeeLimit != 15_000_000_000 {
+		t.Fatalf("params %+v", params)
+	}
+}
@@ -0,0 +1,185 @@
+package tron
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/PandaManPMC/gotron-sdk/pkg/proto/core"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// SetPermissionId 设置所有合约的 Permission_id，会改变 txID，须在签名前调用
+func SetPermissionId(transaction *core.Transaction, permissionId int32) error {
+	if len(transaction.GetSignature()) > 0 {
+		return errors.New("transaction already signed")
+	}
+	for _, contract := range transaction.GetRawData().GetContract() {
+		contract.PermissionId = permissionId
+	}
+	return nil
+}
+
+// SignTxId 对 txID 签名，供多签参与者各自离线签名后交给 AddSignature 汇总
+func SignTxId(txId string, privateKey string) ([]byte, error) {
+	hash, err := hex.DecodeString(txId)
+	if err != nil || len(hash) != 32 {
+		return nil, fmt.Errorf("invalid txID: %s", txId)
+	}
+	privateBytes, err := hex.DecodeString(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("hex decode private key error: %v", err)
+	}
+	priv, err := crypto.ToECDSA(privateBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %v", err)
+	}
+	defer zeroKey(priv)
+	signature, err := crypto.Sign(hash, priv)
+	if err != nil {
+		return nil, fmt.Errorf("sign error: %v", err)
+	}
+	return signature, nil
+}
+
+// AddSignature 追加一个签名，签名顺序任意；校验签名属于该交易且签名者不重复，返回签名者地址
+func AddSignature(transaction *core.Transaction, signature []byte) (string, error) {
+	signers, err := RecoverSigners(&core.Transaction{
+		RawData:   transaction.GetRawData(),
+		Signature: [][]byte{signature},
+	})
+	if err != nil {
+		return "", err
+	}
+	existing, err := RecoverSigners(transaction)
+	if err != nil {
+		return "", err
+	}
+	for _, signer := range existing {
+		if signer == signers[0] {
+			return "", fmt.Errorf("duplicate signature from %s", signer)
+		}
+	}
+	transaction.Signature = append(transaction.Signature, signature)
+	return signers[0], nil
+}
+
+// MultiSign 用 privateKey 签名并按 permission 计算累计权重，Satisfied 为 true 时即可广播；出错时交易保持不变
+func MultiSign(transaction *core.Transaction, privateKey string, permission *core.Permission) (*PermissionCheck, error) {
+	txId, err := TxId(transaction)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := SignTxId(txId, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	// 先在副本上校验，签名者不属于该权限等错误时不修改原交易
+	signatures := transaction.GetSignature()
+	signed := &core.Transaction{
+		RawData:   transaction.GetRawData(),
+		Signature: append(signatures[:len(signatures):len(signatures)], signature),
+	}
+	check, err := CheckPermission(signed, permission)
+	if err != nil {
+		return nil, err
+	}
+	transaction.Signature = signed.Signature
+	return check, nil
+}
+
+// PermissionKey 权限中的地址与权重
+type PermissionKey struct {
+	Address string `json:"address"`
+	Weight  int64  `json:"weight"`
+}
+
+// PermissionDef 权限定义，Operations 仅 active 权限使用，为允许执行的合约类型
+type PermissionDef struct {
+	Name       string                                   `json:"name"`
+	Threshold  int64                                    `json:"threshold"`
+	Keys       []*PermissionKey                         `json:"keys"`
+	Operations []core.Transaction_Contract_ContractType `json:"operations"`
+}
+
+// Operations 合约类型转为 32 字节操作位图
+func Operations(contractTypes ...core.Transaction_Contract_ContractType) []byte {
+	operations := make([]byte, 32)
+	for _, contractType := range contractTypes {
+		i := int(contractType)
+		operations[i/8] |= 1 << (i % 8)
+	}
+	return operations
+}
+
+// Permission 转为链上的权限结构，permissionType 为 owner 时 id 为 0，witness 为 1，active 从 2 开始
+func (p *PermissionDef) Permission(permissionType core.Permission_PermissionType, id int32) (*core.Permission, error) {
+	if p.Threshold <= 0 {
+		return nil, errors.New("permission threshold must be positive")
+	}
+	if len(p.Keys) == 0 || len(p.Keys) > 5 {
+		return nil, fmt.Errorf("permission keys count %d out of range 1-5", len(p.Keys))
+	}
+	permission := &core.Permission{
+		Type:           permissionType,
+		Id:             id,
+		PermissionName: p.Name,
+		Threshold:      p.Threshold,
+	}
+	totalWeight := int64(0)
+	for _, key := range p.Keys {
+		address, err := decodeAddress(key.Address)
+		if err != nil {
+			return nil, err
+		}
+		if key.Weight <= 0 {
+			return nil, fmt.Errorf("key %s weight must be positive", key.Address)
+		}
+		totalWeight += key.Weight
+		permission.Keys = append(permission.Keys, &core.Key{Address: address, Weight: key.Weight})
+	}
+	if totalWeight < p.Threshold {
+		return nil, fmt.Errorf("total weight %d less than threshold %d", totalWeight, p.Threshold)
+	}
+	if permissionType == core.Permission_Active {
+		if len(p.Operations) == 0 {
+			return nil, errors.New("active permission miss operations")
+		}
+		permission.Operations = Operations(p.Operations...)
+	}
+	return permission, nil
+}
+
+// NewAccountPermissionUpdateTx 修改账户权限，owner 与 actives 必填，witness 仅超级代表账户使用可为空。
+// 该交易须满足当前 owner 权限，执行时燃烧 100 TRX
+func NewAccountPermissionUpdateTx(block *BlockRef, ownerAddress string, owner *PermissionDef, witness *PermissionDef, actives []*PermissionDef, opt *TxOption) (*core.Transaction, error) {
+	address, err := decodeAddress(ownerAddress)
+	if err != nil {
+		return nil, err
+	}
+	if owner == nil || len(actives) == 0 {
+		return nil, errors.New("owner and active permission required")
+	}
+	if len(actives) > 8 {
+		return nil, fmt.Errorf("active permission count %d more than 8", len(actives))
+	}
+	contract := &core.AccountPermissionUpdateContract{OwnerAddress: address}
+	if contract.Owner, err = owner.Permission(core.Permission_Owner, 0); err != nil {
+		return nil, fmt.Errorf("owner permission: %v", err)
+	}
+	if witness != nil {
+		if len(witness.Keys) != 1 {
+			return nil, errors.New("witness permission must have exactly one key")
+		}
+		if contract.Witness, err = witness.Permission(core.Permission_Witness, 1); err != nil {
+			return nil, fmt.Errorf("witness permission: %v", err)
+		}
+	}
+	for i, active := range actives {
+		permission, err := active.Permission(core.Permission_Active, int32(i+2))
+		if err != nil {
+			return nil, fmt.Errorf("active permission %d: %v", i, err)
+		}
+		contract.Actives = append(contract.Actives, permission)
+	}
+	return newTransaction(block, core.Transaction_Contract_AccountPermissionUpdateContract, contract, opt, false)
+}
@@ -0,0 +1,101 @@
+package tron
+
+import (
+	"encoding/hex"
+	"github.com/PandaManPMC/gotron-sdk/pkg/proto/core"
+	"github.com/PandaManPMC/txBuilder/tronWal"
+	"github.com/ethereum/go-ethereum/crypto"
+	"testing"
+)
+
+func TestMultiSign(t *testing.T) {
+	keys := make([]string, 3)
+	addresses := make([]string, 3)
+	for i := range keys {
+		privateKey, address, err := tronWal.ImportWallet(testMnemonic, i)
+		if nil != err {
+			t.Fatal(err)
+		}
+		keys[i], addresses[i] = hex.EncodeToString(crypto.FromECDSA(privateKey)), address
+	}
+
+	// 2/3 多签的 active 权限
+	active := &PermissionDef{
+		Name:       "transfer",
+		Threshold:  2,
+		Keys:       []*PermissionKey{{addresses[0], 1}, {addresses[1], 1}, {addresses[2], 1}},
+		Operations: []core.Transaction_Contract_ContractType{core.Transaction_Contract_TransferContract},
+	}
+	permission, err := active.Permission(core.Permission_Active, 2)
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	tx, _ := NewTransferTx(testBlock, addresses[0], "TVTV9aEDdszTNYayNBdjpQ7xfXH3DMyzXq", 5, nil)
+	if err := SetPermissionId(tx, 2); nil != err {
+		t.Fatal(err)
+	}
+	txId, _ := TxId(tx)
+
+	// 参与者离线签名，任意顺序汇总
+	signature2, _ := SignTxId(txId, keys[2])
+	signer, err := AddSignature(tx, signature2)
+	if nil != err || signer != addresses[2] {
+		t.Fatalf("signer %s %v", signer, err)
+	}
+	if _, err := AddSignature(tx, signature2); nil == err {
+		t.Fatal("expected duplicate signature error")
+	}
+	outsider, _, _ := tronWal.ImportWallet(testMnemonic, 3)
+	if _, err := MultiSign(tx, hex.EncodeToString(crypto.FromECDSA(outsider)), permission); nil == err {
+		t.Fatal("expected signer not in permission error")
+	}
+	if len(tx.Signature) != 1 {
+		t.Fatalf("failed MultiSign must not add signature, got %d", len(tx.Signature))
+	}
+	check, err := MultiSign(tx, keys[0], permission)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if check.Weight != 2 || !check.Satisfied || check.Signers[0] != addresses[2] {
+		t.Fatalf("check %+v", check)
+	}
+	if err := SetPermissionId(tx, 3); nil == err {
+		t.Fatal("expected already signed error")
+	}
+}
+
+func TestNewAccountPermissionUpdateTx(t *testing.T) {
+	privateKey, owner := testKey(t)
+	_, other, _ := tronWal.ImportWallet(testMnemonic, 1)
+	tx, err := NewAccountPermissionUpdateTx(testBlock, owner,
+		&PermissionDef{Name: "owner", Threshold: 1, Keys: []*PermissionKey{{owner, 1}}},
+		nil,
+		[]*PermissionDef{{
+			Name:       "active0",
+			Threshold:  2,
+			Keys:       []*PermissionKey{{owner, 1}, {other, 1}},
+			Operations: []core.Transaction_Contract_ContractType{core.Transaction_Contract_TransferContract, core.Transaction_Contract_TriggerSmartContract},
+		}}, nil)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if _, err := Sign(tx, privateKey); nil != err {
+		t.Fatal(err)
+	}
+	decoded, err := DecodeTransaction(tx)
+	if nil != err {
+		t.Fatal(err)
+	}
+	parameter := decoded.Contracts[0].Parameter.(*AccountPermissionUpdateParameter)
+	if parameter.Owner.Threshold != 1 || len(parameter.Actives) != 1 || parameter.Actives[0].Id != 2 ||
+		len(parameter.Actives[0].Operations) != 2 || parameter.Actives[0].Keys[1].Address != other {
+		t.Fatalf("parameter %+v", parameter.Actives[0])
+	}
+
+	if _, err := NewAccountPermissionUpdateTx(testBlock, owner,
+		&PermissionDef{Threshold: 3, Keys: []*PermissionKey{{owner, 1}}}, nil,
+		[]*PermissionDef{{Threshold: 1, Keys: []*PermissionKey{{owner, 1}}, Operations: []core.Transaction_Contract_ContractType{core.Transaction_Contract_TransferContract}}}, nil); nil == err {
+		t.Fatal("expected threshold error")
+	}
+}
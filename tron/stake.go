@@ -0,0 +1,189 @@
+package tron
+
+import (
+	"errors"
+	"fmt"
+	"github.com/PandaManPMC/gotron-sdk/pkg/proto/core"
+	"strings"
+)
+
+// 资源类型
+const (
+	ResourceBandwidth = core.ResourceCode_BANDWIDTH
+	ResourceEnergy    = core.ResourceCode_ENERGY
+	ResourceTronPower = core.ResourceCode_TRON_POWER
+)
+
+const (
+	// MinStakeAmount 质押、代理的最小数量 1 TRX，解质押与收回代理只要求大于 0
+	MinStakeAmount = int64(1_000_000)
+	// MaxVoteCount 一次最多给 30 个超级代表投票
+	MaxVoteCount = 30
+)
+
+// ParseResource 资源名称转枚举，支持 BANDWIDTH、ENERGY、TRON_POWER（不区分大小写）
+func ParseResource(name string) (core.ResourceCode, error) {
+	resource, ok := core.ResourceCode_value[strings.ToUpper(name)]
+	if !ok {
+		return 0, fmt.Errorf("unknown resource: %s", name)
+	}
+	return core.ResourceCode(resource), nil
+}
+
+// NewFreezeBalanceV2Tx Stake 2.0 质押 TRX 获取资源，amount 单位 sun
+func NewFreezeBalanceV2Tx(block *BlockRef, owner string, amount int64, resource core.ResourceCode, opt *TxOption) (*core.Transaction, error) {
+	if amount < MinStakeAmount {
+		return nil, fmt.Errorf("freeze amount %d less than %d", amount, MinStakeAmount)
+	}
+	if resource != ResourceBandwidth && resource != ResourceEnergy {
+		return nil, fmt.Errorf("freeze not support resource %s", resource)
+	}
+	ownerAddress, err := decodeAddress(owner)
+	if err != nil {
+		return nil, err
+	}
+	return newTransaction(block, core.Transaction_Contract_FreezeBalanceV2Contract, &core.FreezeBalanceV2Contract{
+		OwnerAddress:  ownerAddress,
+		FrozenBalance: amount,
+		Resource:      resource,
+	}, opt, false)
+}
+
+// NewUnfreezeBalanceV2Tx 解质押，14 天后可通过 WithdrawExpireUnfreeze 提取
+func NewUnfreezeBalanceV2Tx(block *BlockRef, owner string, amount int64, resource core.ResourceCode, opt *TxOption) (*core.Transaction, error) {
+	if amount <= 0 {
+		return nil, errors.New("unfreeze amount must be positive")
+	}
+	if resource != ResourceBandwidth && resource != ResourceEnergy {
+		return nil, fmt.Errorf("unfreeze not support resource %s", resource)
+	}
+	ownerAddress, err := decodeAddress(owner)
+	if err != nil {
+		return nil, err
+	}
+	return newTransaction(block, core.Transaction_Contract_UnfreezeBalanceV2Contract, &core.UnfreezeBalanceV2Contract{
+		OwnerAddress:    ownerAddress,
+		UnfreezeBalance: amount,
+		Resource:        resource,
+	}, opt, false)
+}
+
+// NewDelegateResourceTx 将质押获得的资源代理给 receiver，amount 为对应的质押 TRX（sun），
+// lockPeriod 大于 0 时锁定的区块数（3 秒一个块），锁定期内不可收回
+func NewDelegateResourceTx(block *BlockRef, owner, receiver string, amount int64, resource core.ResourceCode, lockPeriod int64, opt *TxOption) (*core.Transaction, error) {
+	if amount < MinStakeAmount {
+		return nil, fmt.Errorf("delegate amount %d less than %d", amount, MinStakeAmount)
+	}
+	contract, err := newDelegateContract(owner, receiver, amount, resource)
+	if err != nil {
+		return nil, err
+	}
+	if lockPeriod < 0 {
+		return nil, errors.New("lock period must not be negative")
+	}
+	return newTransaction(block, core.Transaction_Contract_DelegateResourceContract, &core.DelegateResourceContract{
+		OwnerAddress:    contract.OwnerAddress,
+		Resource:        contract.Resource,
+		Balance:         contract.Balance,
+		ReceiverAddress: contract.ReceiverAddress,
+		Lock:            lockPeriod > 0,
+		LockPeriod:      lockPeriod,
+	}, opt, false)
+}
+
+// NewUnDelegateResourceTx 收回代理给 receiver 的资源
+func NewUnDelegateResourceTx(block *BlockRef, owner, receiver string, amount int64, resource core.ResourceCode, opt *TxOption) (*core.Transaction, error) {
+	contract, err := newDelegateContract(owner, receiver, amount, resource)
+	if err != nil {
+		return nil, err
+	}
+	return newTransaction(block, core.Transaction_Contract_UnDelegateResourceContract, contract, opt, false)
+}
+
+func newDelegateContract(owner, receiver string, amount int64, resource core.ResourceCode) (*core.UnDelegateResourceContract, error) {
+	if amount <= 0 {
+		return nil, errors.New("delegate amount must be positive")
+	}
+	if resource != ResourceBandwidth && resource != ResourceEnergy {
+		return nil, fmt.Errorf("delegate not support resource %s", resource)
+	}
+	if owner == receiver {
+		return nil, errors.New("receiver must not be owner")
+	}
+	ownerAddress, err := decodeAddress(owner)
+	if err != nil {
+		return nil, err
+	}
+	receiverAddress, err := decodeAddress(receiver)
+	if err != nil {
+		return nil, err
+	}
+	return &core.UnDelegateResourceContract{
+		OwnerAddress:    ownerAddress,
+		Resource:        resource,
+		Balance:         amount,
+		ReceiverAddress: receiverAddress,
+	}, nil
+}
+
+// NewWithdrawExpireUnfreezeTx 提取已过等待期的解质押 TRX
+func NewWithdrawExpireUnfreezeTx(block *BlockRef, owner string, opt *TxOption) (*core.Transaction, error) {
+	ownerAddress, err := decodeAddress(owner)
+	if err != nil {
+		return nil, err
+	}
+	return newTransaction(block, core.Transaction_Contract_WithdrawExpireUnfreezeContract, &core.WithdrawExpireUnfreezeContract{
+		OwnerAddress: ownerAddress,
+	}, opt, false)
+}
+
+// NewCancelAllUnfreezeV2Tx 取消所有未完成的解质押，未到期的重新质押，已到期的提取
+func NewCancelAllUnfreezeV2Tx(block *BlockRef, owner string, opt *TxOption) (*core.Transaction, error) {
+	ownerAddress, err := decodeAddress(owner)
+	if err != nil {
+		return nil, err
+	}
+	return newTransaction(block, core.Transaction_Contract_CancelAllUnfreezeV2Contract, &core.CancelAllUnfreezeV2Contract{
+		OwnerAddress: ownerAddress,
+	}, opt, false)
+}
+
+// Vote 投票对象，Count 为 TRON Power 数量（1 TRX 质押对应 1 票）
+type Vote struct {
+	Address string `json:"vote_address"`
+	Count   int64  `json:"vote_count"`
+}
+
+// NewVoteWitnessTx 给超级代表投票，新投票会覆盖之前的全部投票
+func NewVoteWitnessTx(block *BlockRef, owner string, votes []*Vote, opt *TxOption) (*core.Transaction, error) {
+	if len(votes) == 0 || len(votes) > MaxVoteCount {
+		return nil, fmt.Errorf("votes count %d out of range 1-%d", len(votes), MaxVoteCount)
+	}
+	ownerAddress, err := decodeAddress(owner)
+	if err != nil {
+		return nil, err
+	}
+	contract := &core.VoteWitnessContract{OwnerAddress: ownerAddress}
+	for _, vote := range votes {
+		if vote.Count <= 0 {
+			return nil, fmt.Errorf("vote count for %s must be positive", vote.Address)
+		}
+		voteAddress, err := decodeAddress(vote.Address)
+		if err != nil {
+			return nil, err
+		}
+		contract.Votes = append(contract.Votes, &core.VoteWitnessContract_Vote{VoteAddress: voteAddress, VoteCount: vote.Count})
+	}
+	return newTransaction(block, core.Transaction_Contract_VoteWitnessContract, contract, opt, false)
+}
+
+// NewWithdrawBalanceTx 领取投票奖励，每 24 小时可领取一次
+func NewWithdrawBalanceTx(block *BlockRef, owner string, opt *TxOption) (*core.Transaction, error) {
+	ownerAddress, err := decodeAddress(owner)
+	if err != nil {
+		return nil, err
+	}
+	return newTransaction(block, core.Transaction_Contract_WithdrawBalanceContract, &core.WithdrawBalanceContract{
+		OwnerAddress: ownerAddress,
+	}, opt, false)
+}
@@ -0,0 +1,103 @@
+package tron
+
+import (
+	"github.com/PandaManPMC/gotron-sdk/pkg/proto/core"
+	"testing"
+)
+
+func TestParseResource(t *testing.T) {
+	resource, err := ParseResource("energy")
+	if nil != err || resource != ResourceEnergy {
+		t.Fatalf("resource %v %v", resource, err)
+	}
+	if _, err := ParseResource("cpu"); nil == err {
+		t.Fatal("expected unknown resource error")
+	}
+}
+
+func TestNewDelegateResourceTx(t *testing.T) {
+	privateKey, owner := testKey(t)
+	receiver := "TVTV9aEDdszTNYayNBdjpQ7xfXH3DMyzXq"
+
+	tx, err := NewDelegateResourceTx(testBlock, owner, receiver, 100_000_000, ResourceEnergy, 28800, nil)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if tx.RawData.FeeLimit != 0 || tx.RawData.Contract[0].Type != core.Transaction_Contract_DelegateResourceContract {
+		t.Fatalf("raw %v", tx.RawData)
+	}
+	if _, err := Sign(tx, privateKey); nil != err {
+		t.Fatal(err)
+	}
+	decoded, err := DecodeTransaction(tx)
+	if nil != err {
+		t.Fatal(err)
+	}
+	parameter := decoded.Contracts[0].Parameter.(*StakeParameter)
+	if parameter.OwnerAddress != owner || parameter.ReceiverAddress != receiver || parameter.Resource != "ENERGY" ||
+		parameter.Balance != 100_000_000 || !parameter.Lock || parameter.LockPeriod != 28800 || decoded.Signers[0] != owner {
+		t.Fatalf("parameter %+v", parameter)
+	}
+
+	if _, err := NewDelegateResourceTx(testBlock, owner, owner, 100_000_000, ResourceEnergy, 0, nil); nil == err {
+		t.Fatal("expected receiver error")
+	}
+	if _, err := NewDelegateResourceTx(testBlock, owner, receiver, 100_000_000, ResourceTronPower, 0, nil); nil == err {
+		t.Fatal("expected resource error")
+	}
+	if _, err := NewDelegateResourceTx(testBlock, owner, receiver, 999_999, ResourceEnergy, 0, nil); nil == err {
+		t.Fatal("expected delegate amount error")
+	}
+	// 收回代理不受 1 TRX 下限限制
+	if _, err := NewUnDelegateResourceTx(testBlock, owner, receiver, 999_999, ResourceBandwidth, nil); nil != err {
+		t.Fatal(err)
+	}
+	if _, err := NewUnDelegateResourceTx(testBlock, owner, receiver, 0, ResourceBandwidth, nil); nil == err {
+		t.Fatal("expected amount error")
+	}
+}
+
+func TestStakeTxs(t *testing.T) {
+	_, owner := testKey(t)
+	freeze, err := NewFreezeBalanceV2Tx(testBlock, owner, 5_000_000, ResourceBandwidth, nil)
+	if nil != err {
+		t.Fatal(err)
+	}
+	decoded, _ := DecodeTransaction(freeze)
+	if p := decoded.Contracts[0].Parameter.(*StakeParameter); p.Balance != 5_000_000 || p.Resource != "BANDWIDTH" {
+		t.Fatalf("freeze %+v", p)
+	}
+	if _, err := NewFreezeBalanceV2Tx(testBlock, owner, 999_999, ResourceBandwidth, nil); nil == err {
+		t.Fatal("expected freeze amount error")
+	}
+	// 解质押只要求大于 0
+	if _, err := NewUnfreezeBalanceV2Tx(testBlock, owner, 1, ResourceEnergy, nil); nil != err {
+		t.Fatal(err)
+	}
+	if _, err := NewUnfreezeBalanceV2Tx(testBlock, owner, 0, ResourceEnergy, nil); nil == err {
+		t.Fatal("expected unfreeze amount error")
+	}
+
+	withdraw, err := NewWithdrawExpireUnfreezeTx(testBlock, owner, nil)
+	if nil != err {
+		t.Fatal(err)
+	}
+	decoded, _ = DecodeTransaction(withdraw)
+	if p := decoded.Contracts[0].Parameter.(*OwnerParameter); p.OwnerAddress != owner || decoded.Contracts[0].Type != "WithdrawExpireUnfreezeContract" {
+		t.Fatalf("withdraw %+v", decoded.Contracts[0])
+	}
+
+	votes := []*Vote{{Address: "TVTV9aEDdszTNYayNBdjpQ7xfXH3DMyzXq", Count: 3}, {Address: "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t", Count: 2}}
+	vote, err := NewVoteWitnessTx(testBlock, owner, votes, nil)
+	if nil != err {
+		t.Fatal(err)
+	}
+	decoded, _ = DecodeTransaction(vote)
+	p := decoded.Contracts[0].Parameter.(*VoteWitnessParameter)
+	if len(p.Votes) != 2 || *p.Votes[0] != *votes[0] || *p.Votes[1] != *votes[1] {
+		t.Fatalf("votes %+v", p.Votes)
+	}
+	if _, err := NewVoteWitnessTx(testBlock, owner, []*Vote{{Address: votes[0].Address}}, nil); nil == err {
+		t.Fatal("expected vote count error")
+	}
+}
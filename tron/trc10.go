@@ -0,0 +1,169 @@
+package tron
+
+import (
+	"errors"
+	"fmt"
+	"github.com/PandaManPMC/gotron-sdk/pkg/proto/core"
+	"strconv"
+)
+
+const (
+	// minTRC10TokenId ALLOW_SAME_TOKEN_NAME 生效后 TRC-10 以自增数字 id 区分，从 1000001 开始
+	minTRC10TokenId = 1000001
+	// AssetIssueFee 发行 TRC-10 燃烧 1024 TRX
+	AssetIssueFee = int64(1024_000_000)
+)
+
+// ValidTRC10TokenId 校验 TRC-10 数字 token id，如旧版 BTT 的 "1002000"
+func ValidTRC10TokenId(tokenId string) bool {
+	id, err := strconv.ParseInt(tokenId, 10, 64)
+	return err == nil && id >= minTRC10TokenId && strconv.FormatInt(id, 10) == tokenId
+}
+
+// NewTransferAssetTx TRC-10 转账，tokenId 为数字 id 字符串，amount 为代币最小单位（按 precision）
+func NewTransferAssetTx(block *BlockRef, from, to, tokenId string, amount int64, opt *TxOption) (*core.Transaction, error) {
+	if !ValidTRC10TokenId(tokenId) {
+		return nil, fmt.Errorf("invalid trc10 token id: %s", tokenId)
+	}
+	if amount <= 0 {
+		return nil, errors.New("amount must be positive")
+	}
+	if from == to {
+		return nil, errors.New("cannot transfer asset to yourself")
+	}
+	owner, err := decodeAddress(from)
+	if err != nil {
+		return nil, err
+	}
+	toAddress, err := decodeAddress(to)
+	if err != nil {
+		return nil, err
+	}
+	return newTransaction(block, core.Transaction_Contract_TransferAssetContract, &core.TransferAssetContract{
+		AssetName:    []byte(tokenId),
+		OwnerAddress: owner,
+		ToAddress:    toAddress,
+		Amount:       amount,
+	}, opt, false)
+}
+
+// FrozenSupply 发行时锁定的代币，Days 天后由发行者解锁
+type FrozenSupply struct {
+	Amount int64 `json:"frozen_amount"`
+	Days   int64 `json:"frozen_days"`
+}
+
+// AssetIssue TRC-10 发行参数，TotalSupply 与 FrozenSupply 为最小单位；
+// 募集期内 TrxNum sun 兑换 Num 个最小单位代币，StartTime、EndTime 为毫秒
+type AssetIssue struct {
+	Name        string          `json:"name"`
+	Abbr        string          `json:"abbr"`
+	TotalSupply int64           `json:"total_supply"`
+	Precision   int32           `json:"precision"`
+	TrxNum      int32           `json:"trx_num"`
+	Num         int32           `json:"num"`
+	StartTime   int64           `json:"start_time"`
+	EndTime     int64           `json:"end_time"`
+	Description string          `json:"description"`
+	Url         string          `json:"url"`
+	Frozen      []*FrozenSupply `json:"frozen_supply"`
+	// FreeAssetNetLimit 每个持有者转账时可用发行者提供的带宽
+	FreeAssetNetLimit int64 `json:"free_asset_net_limit"`
+	// PublicFreeAssetNetLimit 所有持有者共享的发行者带宽
+	PublicFreeAssetNetLimit int64 `json:"public_free_asset_net_limit"`
+}
+
+func (a *AssetIssue) validate(block *BlockRef) error {
+	if !validAssetBytes(a.Name, 32) || a.Name == "trx" {
+		return fmt.Errorf("invalid asset name: %s", a.Name)
+	}
+	if a.Abbr != "" && !validAssetBytes(a.Abbr, 5) {
+		return fmt.Errorf("invalid asset abbr: %s", a.Abbr)
+	}
+	if a.TotalSupply <= 0 {
+		return errors.New("total supply must be positive")
+	}
+	if a.Precision < 0 || a.Precision > 6 {
+		return fmt.Errorf("precision %d out of range 0-6", a.Precision)
+	}
+	if a.TrxNum <= 0 || a.Num <= 0 {
+		return errors.New("trx num and num must be positive")
+	}
+	if a.StartTime <= block.Timestamp {
+		return errors.New("start time must be later than block time")
+	}
+	if a.EndTime <= a.StartTime {
+		return errors.New("end time must be later than start time")
+	}
+	if len(a.Url) == 0 || len(a.Url) > 256 {
+		return errors.New("url length out of range 1-256")
+	}
+	if len(a.Description) > 200 {
+		return errors.New("description length more than 200")
+	}
+	if len(a.Frozen) > 10 {
+		return fmt.Errorf("frozen supply count %d more than 10", len(a.Frozen))
+	}
+	remain := a.TotalSupply
+	for _, frozen := range a.Frozen {
+		if frozen.Amount <= 0 || frozen.Days < 1 || frozen.Days > 3652 {
+			return fmt.Errorf("invalid frozen supply %d for %d days", frozen.Amount, frozen.Days)
+		}
+		if remain -= frozen.Amount; remain < 0 {
+			return errors.New("frozen supply more than total supply")
+		}
+	}
+	if a.FreeAssetNetLimit < 0 || a.PublicFreeAssetNetLimit < 0 {
+		return errors.New("free asset net limit must not be negative")
+	}
+	return nil
+}
+
+// validAssetBytes 名称与简称只允许可见 ASCII 字符
+func validAssetBytes(s string, maxLen int) bool {
+	if len(s) == 0 || len(s) > maxLen {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x21 || s[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// NewAssetIssueTx 发行 TRC-10，每个账户只能发行一次，执行时燃烧 AssetIssueFee，token id 由链上分配
+func NewAssetIssueTx(block *BlockRef, owner string, asset *AssetIssue, opt *TxOption) (*core.Transaction, error) {
+	if asset == nil || block == nil {
+		return nil, errors.New("asset issue and block ref required")
+	}
+	if err := asset.validate(block); err != nil {
+		return nil, err
+	}
+	ownerAddress, err := decodeAddress(owner)
+	if err != nil {
+		return nil, err
+	}
+	contract := &core.AssetIssueContract{
+		OwnerAddress:            ownerAddress,
+		Name:                    []byte(asset.Name),
+		Abbr:                    []byte(asset.Abbr),
+		TotalSupply:             asset.TotalSupply,
+		TrxNum:                  asset.TrxNum,
+		Precision:               asset.Precision,
+		Num:                     asset.Num,
+		StartTime:               asset.StartTime,
+		EndTime:                 asset.EndTime,
+		Description:             []byte(asset.Description),
+		Url:                     []byte(asset.Url),
+		FreeAssetNetLimit:       asset.FreeAssetNetLimit,
+		PublicFreeAssetNetLimit: asset.PublicFreeAssetNetLimit,
+	}
+	for _, frozen := range asset.Frozen {
+		contract.FrozenSupply = append(contract.FrozenSupply, &core.AssetIssueContract_FrozenSupply{
+			FrozenAmount: frozen.Amount,
+			FrozenDays:   frozen.Days,
+		})
+	}
+	return newTransaction(block, core.Transaction_Contract_AssetIssueContract, contract, opt, false)
+}
@@ -0,0 +1,74 @@
+package tron
+
+import (
+	"testing"
+)
+
+func TestNewTransferAssetTx(t *testing.T) {
+	privateKey, from := testKey(t)
+	to := "TVTV9aEDdszTNYayNBdjpQ7xfXH3DMyzXq"
+	tx, err := NewTransferAssetTx(testBlock, from, to, "1002000", 1_000_000, nil)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if _, err := Sign(tx, privateKey); nil != err {
+		t.Fatal(err)
+	}
+	decoded, err := DecodeTransaction(tx)
+	if nil != err {
+		t.Fatal(err)
+	}
+	parameter := decoded.Contracts[0].Parameter.(*TransferAssetParameter)
+	if decoded.Contracts[0].Type != "TransferAssetContract" || parameter.TokenId != "1002000" ||
+		parameter.OwnerAddress != from || parameter.ToAddress != to || parameter.Amount != 1_000_000 {
+		t.Fatalf("parameter %+v", parameter)
+	}
+
+	for _, tokenId := range []string{"", "BTT", "1000000", "01002000"} {
+		if _, err := NewTransferAssetTx(testBlock, from, to, tokenId, 1, nil); nil == err {
+			t.Fatalf("expected invalid token id %q", tokenId)
+		}
+	}
+	if _, err := NewTransferAssetTx(testBlock, from, from, "1002000", 1, nil); nil == err {
+		t.Fatal("expected transfer to yourself error")
+	}
+}
+
+func TestNewAssetIssueTx(t *testing.T) {
+	_, owner := testKey(t)
+	asset := &AssetIssue{
+		Name:        "TestToken",
+		Abbr:        "TT",
+		TotalSupply: 1_000_000_000_000,
+		Precision:   6,
+		TrxNum:      1_000_000,
+		Num:         1_000_000,
+		StartTime:   testBlock.Timestamp + 60_000,
+		EndTime:     testBlock.Timestamp + 86_400_000,
+		Description: "test token",
+		Url:         "https://example.com",
+		Frozen:      []*FrozenSupply{{Amount: 100_000_000_000, Days: 30}},
+	}
+	tx, err := NewAssetIssueTx(testBlock, owner, asset, nil)
+	if nil != err {
+		t.Fatal(err)
+	}
+	decoded, err := DecodeTransaction(tx)
+	if nil != err {
+		t.Fatal(err)
+	}
+	parameter := decoded.Contracts[0].Parameter.(*AssetIssueParameter)
+	if parameter.OwnerAddress != owner || parameter.Name != asset.Name || parameter.TotalSupply != asset.TotalSupply ||
+		parameter.Precision != 6 || len(parameter.Frozen) != 1 || *parameter.Frozen[0] != *asset.Frozen[0] {
+		t.Fatalf("parameter %+v", parameter)
+	}
+
+	asset.Precision = 7
+	if _, err := NewAssetIssueTx(testBlock, owner, asset, nil); nil == err {
+		t.Fatal("expected precision error")
+	}
+	asset.Precision, asset.StartTime = 6, testBlock.Timestamp
+	if _, err := NewAssetIssueTx(testBlock, owner, asset, nil); nil == err {
+		t.Fatal("expected start time error")
+	}
+}
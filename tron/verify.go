@@ -0,0 +1,94 @@
+package tron
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/PandaManPMC/gotron-sdk/pkg/proto/core"
+	"github.com/PandaManPMC/txBuilder/tronWal"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// RecoverSigners 按签名顺序恢复签名地址
+func RecoverSigners(transaction *core.Transaction) ([]string, error) {
+	txId, err := TxId(transaction)
+	if err != nil {
+		return nil, err
+	}
+	hash, _ := hex.DecodeString(txId)
+	signers := make([]string, len(transaction.GetSignature()))
+	for i, signature := range transaction.GetSignature() {
+		if len(signature) != crypto.SignatureLength {
+			return nil, fmt.Errorf("signature %d invalid length %d", i, len(signature))
+		}
+		sig := make([]byte, len(signature))
+		copy(sig, signature)
+		if sig[crypto.RecoveryIDOffset] >= 27 {
+			sig[crypto.RecoveryIDOffset] -= 27
+		}
+		pubKey, err := crypto.SigToPub(hash, sig)
+		if err != nil {
+			return nil, fmt.Errorf("signature %d recover error: %v", i, err)
+		}
+		signers[i] = tronWal.PubKeyToAddressTron(*pubKey)
+	}
+	return signers, nil
+}
+
+// PermissionCheck 签名与权限的校验结果
+type PermissionCheck struct {
+	Signers   []string `json:"signers"`
+	Weight    int64    `json:"weight"`
+	Threshold int64    `json:"threshold"`
+	// Satisfied 累计权重达到阈值
+	Satisfied bool `json:"satisfied"`
+}
+
+// CheckPermission 校验签名是否满足账户权限：合约的 Permission_id 须与 permission.Id 一致，
+// active 权限须包含该合约类型的操作位，签名者须在 keys 中且不重复，累计权重与阈值比较。
+// 普通账户未修改权限时，owner 权限为自身地址、权重 1、阈值 1
+func CheckPermission(transaction *core.Transaction, permission *core.Permission) (*PermissionCheck, error) {
+	if permission == nil {
+		return nil, errors.New("permission miss")
+	}
+	for _, contract := range transaction.GetRawData().GetContract() {
+		if contract.PermissionId != permission.Id {
+			return nil, fmt.Errorf("contract permission id %d not match permission %d", contract.PermissionId, permission.Id)
+		}
+		if permission.Type == core.Permission_Active && !operationAllowed(permission.Operations, contract.Type) {
+			return nil, fmt.Errorf("permission %d not allow %s", permission.Id, contract.Type)
+		}
+	}
+	signers, err := RecoverSigners(transaction)
+	if err != nil {
+		return nil, err
+	}
+	weights := make(map[string]int64, len(permission.Keys))
+	for _, key := range permission.Keys {
+		weights[tronWal.EncodeCheck(key.Address)] = key.Weight
+	}
+	check := &PermissionCheck{Signers: signers, Threshold: permission.Threshold}
+	seen := make(map[string]bool, len(signers))
+	for _, signer := range signers {
+		if seen[signer] {
+			return nil, fmt.Errorf("duplicate signature from %s", signer)
+		}
+		seen[signer] = true
+		weight, ok := weights[signer]
+		if !ok {
+			return nil, fmt.Errorf("signer %s not in permission %d", signer, permission.Id)
+		}
+		check.Weight += weight
+	}
+	check.Satisfied = check.Weight >= permission.Threshold
+	return check, nil
+}
+
+// operationAllowed operations 为 32 字节位图，第 n 位对应合约类型 n
+func operationAllowed(operations []byte, contractType core.Transaction_Contract_ContractType) bool {
+	i := int(contractType)
+	if i/8 >= len(operations) {
+		return false
+	}
+	return operations[i/8]&(1<<(i%8)) != 0
+}
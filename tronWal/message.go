@@ -0,0 +1,66 @@
+package tronWal
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"strconv"
+	"strings"
+)
+
+// messagePrefixV2 TIP-191 前缀，与 TronWeb/TronLink signMessageV2 一致
+const messagePrefixV2 = "\x19TRON Signed Message:\n"
+
+// MessageHashV2 signMessageV2 摘要：keccak256("\x19TRON Signed Message:\n" + len(message) + message)
+func MessageHashV2(message []byte) []byte {
+	return crypto.Keccak256([]byte(messagePrefixV2+strconv.Itoa(len(message))), message)
+}
+
+// SignMessageV2 signMessageV2 签名，返回 0x 开头的 65 字节 r||s||v hex，v 为 27/28，与 TronLink 输出一致
+func SignMessageV2(message []byte, privateKey *ecdsa.PrivateKey) (string, error) {
+	sig, err := crypto.Sign(MessageHashV2(message), privateKey)
+	if nil != err {
+		return "", fmt.Errorf("sign error: %v", err)
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+	return hexutil.Encode(sig), nil
+}
+
+// RecoverMessageV2 从 signMessageV2 签名恢复 base58 地址，签名可不带 0x，v 支持 0/1 和 27/28
+func RecoverMessageV2(message []byte, signature string) (string, error) {
+	if !strings.HasPrefix(signature, "0x") && !strings.HasPrefix(signature, "0X") {
+		signature = "0x" + signature
+	}
+	sig, err := hexutil.Decode(signature)
+	if nil != err {
+		return "", fmt.Errorf("decode signature error: %v", err)
+	}
+	if len(sig) != crypto.SignatureLength {
+		return "", fmt.Errorf("invalid signature length: %d", len(sig))
+	}
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+	if sig[crypto.RecoveryIDOffset] > 1 {
+		return "", errors.New("invalid signature recovery id")
+	}
+	pubKey, err := crypto.SigToPub(MessageHashV2(message), sig)
+	if nil != err {
+		return "", fmt.Errorf("recover public key error: %v", err)
+	}
+	return PubKeyToAddressTron(*pubKey), nil
+}
+
+// VerifyMessageV2 校验 signMessageV2 签名是否由 address 签出，用于 DApp 登录鉴权
+func VerifyMessageV2(message []byte, signature, address string) (bool, error) {
+	if !ValidAddress(address) {
+		return false, fmt.Errorf("invalid tron address: %s", address)
+	}
+	recovered, err := RecoverMessageV2(message, signature)
+	if nil != err {
+		return false, err
+	}
+	return recovered == address, nil
+}
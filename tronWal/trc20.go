@@ -0,0 +1,52 @@
+package tronWal
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/PandaManPMC/txBuilder/evmAbi"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+)
+
+// TronAddressToEvm tron 地址去掉 0x41 前缀，得到合约调用参数中使用的 20 字节地址
+func TronAddressToEvm(address string) (common.Address, error) {
+	decoded, err := DecodeCheck(address)
+	if nil != err {
+		return common.Address{}, err
+	}
+	if len(decoded) != 21 || decoded[0] != 0x41 {
+		return common.Address{}, fmt.Errorf("invalid tron address: %s", address)
+	}
+	return common.BytesToAddress(decoded[1:]), nil
+}
+
+// EvmToTronAddress 合约返回或事件中的 20 字节地址转为 tron 地址
+func EvmToTronAddress(address common.Address) string {
+	return EncodeCheck(append([]byte{0x41}, address.Bytes()...))
+}
+
+// TRC20TransferData TRC-20 transfer(address,uint256) 的调用数据 hex，用于 TriggerSmartContract
+func TRC20TransferData(to string, amount *big.Int) (string, error) {
+	addr, err := TronAddressToEvm(to)
+	if nil != err {
+		return "", err
+	}
+	data, err := evmAbi.ERC20Transfer(addr, amount)
+	if nil != err {
+		return "", err
+	}
+	return hex.EncodeToString(data), nil
+}
+
+// TRC20ApproveData TRC-20 approve(address,uint256) 的调用数据 hex
+func TRC20ApproveData(spender string, amount *big.Int) (string, error) {
+	addr, err := TronAddressToEvm(spender)
+	if nil != err {
+		return "", err
+	}
+	data, err := evmAbi.ERC20Approve(addr, amount)
+	if nil != err {
+		return "", err
+	}
+	return hex.EncodeToString(data), nil
+}
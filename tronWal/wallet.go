@@ -130,3 +130,18 @@ func ImportWallet(mnemonic string, index int) (privateKey *ecdsa.PrivateKey, add
 	address = TronAddressByPrivateKey(privateKey)
 	return
 }
+
+// ImportKeystore 导入 keystore V3 JSON，格式与 ethWal 相同，只是地址按 tron 格式返回
+func ImportKeystore(keystoreJson []byte, password string) (privateKey *ecdsa.PrivateKey, address string, err error) {
+	privateKey, err = hdWallet.GetInstanceByHDWalletUtil().DecryptKeystore(keystoreJson, password)
+	if nil != err {
+		return nil, "", err
+	}
+	address = TronAddressByPrivateKey(privateKey)
+	return
+}
+
+// ExportKeystore 导出 keystore V3 JSON，kdf 见 hdWallet.KdfScrypt 等
+func ExportKeystore(privateKey *ecdsa.PrivateKey, password string, kdf string) ([]byte, error) {
+	return hdWallet.GetInstanceByHDWalletUtil().EncryptKeystore(privateKey, password, kdf)
+}
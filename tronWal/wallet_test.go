@@ -4,6 +4,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"github.com/PandaManPMC/txBuilder/hdWallet"
+	"math/big"
 	"testing"
 )
 
@@ -124,3 +125,85 @@ func TestValidAddress(t *testing.T) {
 	t.Log(ValidAddress(""))
 
 }
+
+func TestTRC20TransferData(t *testing.T) {
+	data, err := TRC20TransferData("TVTV9aEDdszTNYayNBdjpQ7xfXH3DMyzXq", big.NewInt(1000000))
+	if nil != err {
+		t.Fatal(err)
+	}
+	padded, _ := HexAddressPadded64("TVTV9aEDdszTNYayNBdjpQ7xfXH3DMyzXq")
+	// HexAddressPadded64 保留了 41 前缀，合约参数只取后 20 字节
+	expect := "a9059cbb" + "000000000000000000000000" + padded[len(padded)-40:] + IntToHexPadded64(1000000)
+	if data != expect {
+		t.Fatalf("transfer data mismatch %s", data)
+	}
+
+	addr, _ := TronAddressToEvm("TVTV9aEDdszTNYayNBdjpQ7xfXH3DMyzXq")
+	if EvmToTronAddress(addr) != "TVTV9aEDdszTNYayNBdjpQ7xfXH3DMyzXq" {
+		t.Fatal("address round trip mismatch")
+	}
+}
+
+func TestKeystore(t *testing.T) {
+	privateKey, address, err := ImportWallet("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", 0)
+	if nil != err {
+		t.Fatal(err)
+	}
+	keystoreJson, err := ExportKeystore(privateKey, "pass", hdWallet.KdfScryptLight)
+	if nil != err {
+		t.Fatal(err)
+	}
+	_, imported, err := ImportKeystore(keystoreJson, "pass")
+	if nil != err {
+		t.Fatal(err)
+	}
+	if imported != address {
+		t.Fatalf("address mismatch %s %s", imported, address)
+	}
+}
+
+func TestSignMessageV2(t *testing.T) {
+	// 固定向量：助记词 abandon...about 第 0 个地址，签名为 RFC6979 确定性签名（与 TronWeb signMessageV2 相同算法）
+	const (
+		vectorAddress   = "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH"
+		vectorHash      = "692131837eb4739008f5d530a698bb2939a117d3d0d20cffa6d2503ae48e228c"
+		vectorSignature = "0x12823dae82b5ab42bbb366bd95cbb060cf7dc86714e37336e7bc00af87dddf58626b653d7632f5282ba5fd8ac95d55210e077d6a64378d389eb7cbdea24e9ef11c"
+	)
+	message := []byte("login nonce 123456")
+	if hex.EncodeToString(MessageHashV2(message)) != vectorHash {
+		t.Fatal("message hash mismatch")
+	}
+	recovered, err := RecoverMessageV2(message, vectorSignature)
+	if nil != err || recovered != vectorAddress {
+		t.Fatalf("recovered %s %v", recovered, err)
+	}
+
+	privateKey, address, err := ImportWallet("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", 0)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if address != vectorAddress {
+		t.Fatalf("address %s", address)
+	}
+	signature, err := SignMessageV2(message, privateKey)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if signature != vectorSignature {
+		t.Fatalf("signature %s", signature)
+	}
+	ok, err := VerifyMessageV2(message, signature, address)
+	if nil != err || !ok {
+		t.Fatalf("verify %v %v", ok, err)
+	}
+	// 不带 0x、v 为 0/1 的签名同样可以恢复
+	raw, _ := hex.DecodeString(vectorSignature[2:])
+	raw[64] -= 27
+	recovered, err = RecoverMessageV2(message, hex.EncodeToString(raw))
+	if nil != err || recovered != vectorAddress {
+		t.Fatalf("recovered %s %v", recovered, err)
+	}
+	if ok, _ := VerifyMessageV2([]byte("login nonce 654321"), signature, address); ok {
+		t.Fatal("expected verify fail for other message")
+	}
+}
@@ -0,0 +1,126 @@
+package txBuilder
+
+import (
+	"errors"
+	"fmt"
+	"github.com/btcsuite/btcd/txscript"
+)
+
+// ErrAssetUtxo 输入携带铭文或符文资产，默认禁止当作普通 UTXO 花费
+var ErrAssetUtxo = errors.New("utxo carries inscription or rune assets")
+
+// UtxoInscription 铭文所在的 sat 在 UTXO 内的偏移
+type UtxoInscription struct {
+	InscriptionId string `json:"inscriptionId"`
+	Offset        int64  `json:"offset"`
+}
+
+// UtxoRune 符文余额，Amount 为 u128 十进制字符串
+type UtxoRune struct {
+	RuneId string `json:"runeId"`
+	Amount string `json:"amount"`
+}
+
+// UtxoAssets UTXO 上的资产标注，由索引服务（ord 等）提供
+type UtxoAssets struct {
+	Inscriptions []*UtxoInscription `json:"inscriptions,omitempty"`
+	Runes        []*UtxoRune        `json:"runes,omitempty"`
+}
+
+func (a *UtxoAssets) HasAsset() bool {
+	return a != nil && (len(a.Inscriptions) > 0 || len(a.Runes) > 0)
+}
+
+// Cardinal 过滤掉携带资产的 UTXO，只保留可用于支付手续费的普通 UTXO
+func (s PrevOutputs) Cardinal() PrevOutputs {
+	cardinal := make(PrevOutputs, 0, len(s))
+	for _, v := range s {
+		if !v.Assets.HasAsset() {
+			cardinal = append(cardinal, v)
+		}
+	}
+	return cardinal
+}
+
+func (s PrevOutputs) checkCardinal() error {
+	for i, v := range s {
+		if v.Assets.HasAsset() {
+			return fmt.Errorf("prev output %d (%s:%d): %w", i, v.TxId, v.VOut, ErrAssetUtxo)
+		}
+	}
+	return nil
+}
+
+// SatLocation 铭文 sat 在交易输出中的位置，OutputIndex 为 -1 表示进入手续费
+type SatLocation struct {
+	InscriptionId string `json:"inscriptionId"`
+	InputIndex    int    `json:"inputIndex"`
+	OutputIndex   int    `json:"outputIndex"`
+	OutputOffset  int64  `json:"outputOffset"`
+}
+
+// LocateSat 按 ordinals 先进先出规则，计算第 inputIndex 个输入中偏移 offset 的 sat 落在哪个输出，
+// ok 为 false 表示该 sat 被当作手续费
+func LocateSat(inputAmounts, outputAmounts []int64, inputIndex int, offset int64) (outputIndex int, outputOffset int64, ok bool) {
+	position := offset
+	for i := 0; i < inputIndex; i++ {
+		position += inputAmounts[i]
+	}
+	for i, amount := range outputAmounts {
+		if position < amount {
+			return i, position, true
+		}
+		position -= amount
+	}
+	return -1, 0, false
+}
+
+// LocateInscriptions 计算所有输入上的铭文在输出中的落点
+func LocateInscriptions(inputAmounts []int64, inputAssets []*UtxoAssets, outputAmounts []int64) []*SatLocation {
+	locations := make([]*SatLocation, 0)
+	for i, assets := range inputAssets {
+		if assets == nil {
+			continue
+		}
+		for _, inscription := range assets.Inscriptions {
+			outputIndex, outputOffset, ok := LocateSat(inputAmounts, outputAmounts, i, inscription.Offset)
+			if !ok {
+				outputIndex = -1
+			}
+			locations = append(locations, &SatLocation{
+				InscriptionId: inscription.InscriptionId,
+				InputIndex:    i,
+				OutputIndex:   outputIndex,
+				OutputOffset:  outputOffset,
+			})
+		}
+	}
+	return locations
+}
+
+// checkAssetRouting 允许花费资产 UTXO 时，确认铭文不会落入手续费或 OP_RETURN 输出，符文不会因全部是 OP_RETURN 输出而被销毁
+func checkAssetRouting(inputAmounts []int64, inputAssets []*UtxoAssets, outputAmounts []int64, outputPkScripts [][]byte) error {
+	for _, location := range LocateInscriptions(inputAmounts, inputAssets, outputAmounts) {
+		if location.OutputIndex < 0 {
+			return fmt.Errorf("inscription %s of input %d would be spent as fee", location.InscriptionId, location.InputIndex)
+		}
+		if txscript.GetScriptClass(outputPkScripts[location.OutputIndex]) == txscript.NullDataTy {
+			return fmt.Errorf("inscription %s of input %d would be burned in OP_RETURN output %d", location.InscriptionId, location.InputIndex, location.OutputIndex)
+		}
+	}
+	hasRune := false
+	for _, assets := range inputAssets {
+		if assets != nil && len(assets.Runes) > 0 {
+			hasRune = true
+		}
+	}
+	if !hasRune {
+		return nil
+	}
+	for _, pkScript := range outputPkScripts {
+		if txscript.GetScriptClass(pkScript) != txscript.NullDataTy {
+			return nil
+		}
+	}
+	return errors.New("rune balances would be burned, no non OP_RETURN output")
+}
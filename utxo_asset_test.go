@@ -0,0 +1,154 @@
+package txBuilder
+
+import (
+	"errors"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"testing"
+)
+
+func TestLocateSat(t *testing.T) {
+	inputAmounts := []int64{10000, 546, 20000}
+	outputAmounts := []int64{546, 10000, 15000}
+
+	cases := []struct {
+		inputIndex   int
+		offset       int64
+		outputIndex  int
+		outputOffset int64
+		ok           bool
+	}{
+		{0, 0, 0, 0, true},
+		{0, 600, 1, 54, true},
+		{1, 0, 1, 9454, true},
+		{2, 0, 2, 0, true},
+		{2, 14999, 2, 14999, true},
+		{2, 15000, -1, 0, false},
+	}
+	for _, c := range cases {
+		outputIndex, outputOffset, ok := LocateSat(inputAmounts, outputAmounts, c.inputIndex, c.offset)
+		if outputIndex != c.outputIndex || outputOffset != c.outputOffset || ok != c.ok {
+			t.Fatalf("input %d offset %d: got %d %d %v", c.inputIndex, c.offset, outputIndex, outputOffset, ok)
+		}
+	}
+}
+
+func TestTransactionBuilderAssetInput(t *testing.T) {
+	key, _ := btcec.NewPrivateKey()
+	net := &chaincfg.TestNet3Params
+	wif, _ := btcutil.NewWIF(key, net, true)
+	addr, _ := btcutil.NewAddressWitnessPubKeyHash(btcutil.Hash160(key.PubKey().SerializeCompressed()), net)
+	assets := &UtxoAssets{Inscriptions: []*UtxoInscription{{InscriptionId: "6fb976ab49dcec017f1e201e84395983204ae1a7c2abf7ced0a85d692e442799i0", Offset: 0}}}
+
+	newBuilder := func() *TransactionBuilder {
+		txBuild := NewTxBuild(2, net)
+		txBuild.AddInput2("453aa6dd39f31f06cd50b72a8683b8c0402ab36f889d96e4ef5ae14fe9b6fc32", 0, wif.String(), addr.EncodeAddress(), 100000)
+		txBuild.AddInputWithAssets("22c8a4db2b3b3a8df3d4c5e1d4a0d1f8a5d7e1f3b0c8b5e0d3f3c8a5b2e1d0c9", 1, wif.String(), addr.EncodeAddress(), 546, assets)
+		txBuild.AddOutput(addr.EncodeAddress(), 99000)
+		return txBuild
+	}
+
+	if _, err := newBuilder().Build(); !errors.Is(err, ErrAssetUtxo) {
+		t.Fatalf("expected ErrAssetUtxo, got %v", err)
+	}
+
+	// 铭文 sat 位于 100000 处，超出输出总额，会被当作手续费
+	txBuild := newBuilder()
+	txBuild.AllowAssetInputs()
+	if _, err := txBuild.Build(); nil == err {
+		t.Fatal("expected inscription fee error")
+	}
+
+	txBuild = newBuilder()
+	txBuild.AllowAssetInputs()
+	txBuild.outputs[0].amount = 100000
+	txBuild.AddOutput(addr.EncodeAddress(), 300)
+	if txBuild.LocateInscriptions()[0].OutputIndex != 1 {
+		t.Fatal("inscription should land on output 1")
+	}
+	if _, err := txBuild.Build(); nil != err {
+		t.Fatal(err)
+	}
+}
+
+func TestInscribeRejectAssetUtxo(t *testing.T) {
+	key, _ := btcec.NewPrivateKey()
+	request := inscribeTestRequest(t, key, 1)
+	request.CommitTxPrevOutputList[0].Assets = &UtxoAssets{Runes: []*UtxoRune{{RuneId: "840000:3", Amount: "1000"}}}
+	if _, err := Inscribe(&chaincfg.TestNet3Params, request); !errors.Is(err, ErrAssetUtxo) {
+		t.Fatalf("expected ErrAssetUtxo, got %v", err)
+	}
+	if len(request.CommitTxPrevOutputList.Cardinal()) != 0 {
+		t.Fatal("cardinal filter failed")
+	}
+}
+
+func TestTransactionBuilderAssetBurn(t *testing.T) {
+	key, _ := btcec.NewPrivateKey()
+	net := &chaincfg.TestNet3Params
+	wif, _ := btcutil.NewWIF(key, net, true)
+	addr, _ := btcutil.NewAddressWitnessPubKeyHash(btcutil.Hash160(key.PubKey().SerializeCompressed()), net)
+	inscriptionId := "6fb976ab49dcec017f1e201e84395983204ae1a7c2abf7ced0a85d692e442799i0"
+	assets := &UtxoAssets{Inscriptions: []*UtxoInscription{{InscriptionId: inscriptionId, Offset: 1000}}}
+
+	newBuilder := func() *TransactionBuilder {
+		txBuild := NewTxBuild(2, net)
+		txBuild.AllowAssetInputs()
+		txBuild.AddInputWithAssets("22c8a4db2b3b3a8df3d4c5e1d4a0d1f8a5d7e1f3b0c8b5e0d3f3c8a5b2e1d0c9", 1, wif.String(), addr.EncodeAddress(), 10000, assets)
+		txBuild.AddInput2("453aa6dd39f31f06cd50b72a8683b8c0402ab36f889d96e4ef5ae14fe9b6fc32", 0, wif.String(), addr.EncodeAddress(), 100000)
+		return txBuild
+	}
+
+	// 带金额的 OP_RETURN 输出会销毁落在其中的铭文
+	txBuild := newBuilder()
+	txBuild.AddOutput2("", "6a0474657374", 5000)
+	txBuild.AddOutput(addr.EncodeAddress(), 100000)
+	if txBuild.LocateInscriptions()[0].OutputIndex != 0 {
+		t.Fatal("inscription should land on OP_RETURN output")
+	}
+	if _, err := txBuild.Build(); nil == err {
+		t.Fatal("expected OP_RETURN burn error")
+	}
+}
+
+func TestTransactionBuilderInscriptionOutput(t *testing.T) {
+	key, _ := btcec.NewPrivateKey()
+	net := &chaincfg.TestNet3Params
+	wif, _ := btcutil.NewWIF(key, net, true)
+	addr, _ := btcutil.NewAddressWitnessPubKeyHash(btcutil.Hash160(key.PubKey().SerializeCompressed()), net)
+	inscriptionId := "6fb976ab49dcec017f1e201e84395983204ae1a7c2abf7ced0a85d692e442799i0"
+	assets := &UtxoAssets{Inscriptions: []*UtxoInscription{{InscriptionId: inscriptionId, Offset: 1000}}}
+
+	newBuilder := func() *TransactionBuilder {
+		txBuild := NewTxBuild(2, net)
+		txBuild.AllowAssetInputs()
+		txBuild.AddInputWithAssets("22c8a4db2b3b3a8df3d4c5e1d4a0d1f8a5d7e1f3b0c8b5e0d3f3c8a5b2e1d0c9", 1, wif.String(), addr.EncodeAddress(), 10000, assets)
+		txBuild.AddInput2("453aa6dd39f31f06cd50b72a8683b8c0402ab36f889d96e4ef5ae14fe9b6fc32", 0, wif.String(), addr.EncodeAddress(), 100000)
+		return txBuild
+	}
+
+	// 铭文位于 sat 1000，先补 1000 的填充输出，接收方输出从铭文 sat 开始
+	txBuild := newBuilder()
+	if err := txBuild.AddInscriptionOutput(inscriptionId, addr.EncodeAddress(), 546, addr.EncodeAddress()); nil != err {
+		t.Fatal(err)
+	}
+	txBuild.AddOutput(addr.EncodeAddress(), 100000)
+	location := txBuild.LocateInscriptions()[0]
+	if len(txBuild.outputs) != 3 || txBuild.outputs[0].amount != 1000 || location.OutputIndex != 1 || location.OutputOffset != 0 {
+		t.Fatalf("location %+v", location)
+	}
+	if _, err := txBuild.Build(); nil != err {
+		t.Fatal(err)
+	}
+	if err := txBuild.AddInscriptionOutput(inscriptionId, addr.EncodeAddress(), 546, addr.EncodeAddress()); nil == err {
+		t.Fatal("expected inscription already covered error")
+	}
+
+	// 填充金额低于 dust
+	txBuild = newBuilder()
+	txBuild.AddOutput(addr.EncodeAddress(), 800)
+	if err := txBuild.AddInscriptionOutput(inscriptionId, addr.EncodeAddress(), 546, addr.EncodeAddress()); nil == err {
+		t.Fatal("expected padding dust error")
+	}
+}